@@ -0,0 +1,283 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// API is the set of Get* methods of Client. Depend on API instead of
+// *Client to substitute fakes or wrap the client with decorators.
+type API interface {
+	GetHealth(opts ...RequestOption) (Health, error)
+	GetAsset(asset_id string, opts ...RequestOption) (AssetResponse, error)
+	GetAssets(params AssetsRequestParams, opts ...RequestOption) (AssetsResponse, error)
+	GetAssetsByIDs(ids []string, opts ...RequestOption) (map[string]Asset, []string, error)
+	GetAssetLog(asset_id string, params LogRequestParams, opts ...RequestOption) (AssetLogResponse, error)
+	GetAssetSales(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) (SalesResponse, error)
+}
+
+// LoggingAPI logs every call to API.
+type LoggingAPI struct {
+	API    API
+	Logger Logger
+}
+
+func NewLoggingAPI(api API, logger Logger) *LoggingAPI {
+	return &LoggingAPI{API: api, Logger: logger}
+}
+
+func (a *LoggingAPI) log(method string, start time.Time, err error) {
+	level := LogInfo
+	fields := []LogField{{"method", method}, {"duration", time.Since(start)}}
+
+	if err != nil {
+		level = LogError
+		if status := statusCode(nil, err); status >= 400 && status < 500 {
+			level = LogWarn
+		}
+		fields = append(fields, LogField{"error", redactError(err, nil)})
+	}
+
+	a.Logger.Log(level, "call", fields...)
+}
+
+func (a *LoggingAPI) GetHealth(opts ...RequestOption) (Health, error) {
+	start := time.Now()
+	res, err := a.API.GetHealth(opts...)
+	a.log("GetHealth", start, err)
+	return res, err
+}
+
+func (a *LoggingAPI) GetAsset(asset_id string, opts ...RequestOption) (AssetResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAsset(asset_id, opts...)
+	a.log("GetAsset", start, err)
+	return res, err
+}
+
+func (a *LoggingAPI) GetAssets(params AssetsRequestParams, opts ...RequestOption) (AssetsResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAssets(params, opts...)
+	a.log("GetAssets", start, err)
+	return res, err
+}
+
+func (a *LoggingAPI) GetAssetsByIDs(ids []string, opts ...RequestOption) (map[string]Asset, []string, error) {
+	start := time.Now()
+	assets, missing, err := a.API.GetAssetsByIDs(ids, opts...)
+	a.log("GetAssetsByIDs", start, err)
+	return assets, missing, err
+}
+
+func (a *LoggingAPI) GetAssetLog(asset_id string, params LogRequestParams, opts ...RequestOption) (AssetLogResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAssetLog(asset_id, params, opts...)
+	a.log("GetAssetLog", start, err)
+	return res, err
+}
+
+func (a *LoggingAPI) GetAssetSales(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) (SalesResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAssetSales(asset_id, params, opts...)
+	a.log("GetAssetSales", start, err)
+	return res, err
+}
+
+// MetricsAPI reports every call to API to Metrics. The method
+// name is used as the endpoint.
+type MetricsAPI struct {
+	API     API
+	Metrics MetricsCollector
+}
+
+func NewMetricsAPI(api API, metrics MetricsCollector) *MetricsAPI {
+	return &MetricsAPI{API: api, Metrics: metrics}
+}
+
+func (a *MetricsAPI) observe(method string, start time.Time, status int, err error) {
+	if err != nil {
+		status = statusCode(nil, err)
+	}
+
+	a.Metrics.ObserveRequest(RequestMetrics{
+		Method:     "GET",
+		Endpoint:   method,
+		StatusCode: status,
+		Duration:   time.Since(start),
+		ErrorKind:  errorKind(err),
+	})
+}
+
+func (a *MetricsAPI) GetHealth(opts ...RequestOption) (Health, error) {
+	start := time.Now()
+	res, err := a.API.GetHealth(opts...)
+	a.observe("GetHealth", start, res.HTTPStatusCode, err)
+	return res, err
+}
+
+func (a *MetricsAPI) GetAsset(asset_id string, opts ...RequestOption) (AssetResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAsset(asset_id, opts...)
+	a.observe("GetAsset", start, res.HTTPStatusCode, err)
+	return res, err
+}
+
+func (a *MetricsAPI) GetAssets(params AssetsRequestParams, opts ...RequestOption) (AssetsResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAssets(params, opts...)
+	a.observe("GetAssets", start, res.HTTPStatusCode, err)
+	return res, err
+}
+
+func (a *MetricsAPI) GetAssetsByIDs(ids []string, opts ...RequestOption) (map[string]Asset, []string, error) {
+	start := time.Now()
+	assets, missing, err := a.API.GetAssetsByIDs(ids, opts...)
+	a.observe("GetAssetsByIDs", start, 200, err)
+	return assets, missing, err
+}
+
+func (a *MetricsAPI) GetAssetLog(asset_id string, params LogRequestParams, opts ...RequestOption) (AssetLogResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAssetLog(asset_id, params, opts...)
+	a.observe("GetAssetLog", start, res.HTTPStatusCode, err)
+	return res, err
+}
+
+func (a *MetricsAPI) GetAssetSales(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) (SalesResponse, error) {
+	start := time.Now()
+	res, err := a.API.GetAssetSales(asset_id, params, opts...)
+	a.observe("GetAssetSales", start, res.HTTPStatusCode, err)
+	return res, err
+}
+
+type cachedResult struct {
+	values  []interface{}
+	expires time.Time
+}
+
+// CachingAPI caches successful results of API for TTL, keyed by the
+// method and its arguments. Request options are not part of the key,
+// but requests with WithNoCache, WithMinBlock or WithMaxLag skip the
+// cache lookup. Their results are still cached.
+// Cached results are shared between callers and must not be modified.
+type CachingAPI struct {
+	API API
+	TTL time.Duration
+
+	mu      sync.Mutex
+	results map[string]cachedResult
+	swept   time.Time
+}
+
+func NewCachingAPI(api API, ttl time.Duration) *CachingAPI {
+	return &CachingAPI{API: api, TTL: ttl}
+}
+
+// skipCache reports whether opts ask for a result that is not cached.
+func skipCache(opts []RequestOption) bool {
+	r := &Request{Context: context.Background(), Query: url.Values{}, Header: http.Header{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r.NoCache || r.MinBlock > 0 || r.MaxLag > 0
+}
+
+// cached returns the cached result for key, or calls fn and caches it.
+func (a *CachingAPI) cached(key string, opts []RequestOption, fn func() ([]interface{}, error)) ([]interface{}, error) {
+	now := time.Now()
+
+	if !skipCache(opts) {
+		a.mu.Lock()
+		r, ok := a.results[key]
+		a.mu.Unlock()
+
+		if ok && !now.After(r.expires) {
+			return r.values, nil
+		}
+	}
+
+	values, err := fn()
+	if err != nil {
+		return values, err
+	}
+
+	a.mu.Lock()
+	if a.results == nil {
+		a.results = make(map[string]cachedResult)
+	}
+	a.sweep(now)
+	a.results[key] = cachedResult{values: values, expires: now.Add(a.TTL)}
+	a.mu.Unlock()
+	return values, nil
+}
+
+// sweep removes expired results at most once per TTL,
+// so results that are never asked for again are not kept forever.
+func (a *CachingAPI) sweep(now time.Time) {
+	if now.Sub(a.swept) < a.TTL {
+		return
+	}
+	a.swept = now
+
+	for key, r := range a.results {
+		if now.After(r.expires) {
+			delete(a.results, key)
+		}
+	}
+}
+
+func cachedKey(method string, args ...interface{}) string {
+	return method + fmt.Sprintf("%#v", args)
+}
+
+func (a *CachingAPI) GetHealth(opts ...RequestOption) (Health, error) {
+	v, err := a.cached(cachedKey("GetHealth"), opts, func() ([]interface{}, error) {
+		res, err := a.API.GetHealth(opts...)
+		return []interface{}{res}, err
+	})
+	return v[0].(Health), err
+}
+
+func (a *CachingAPI) GetAsset(asset_id string, opts ...RequestOption) (AssetResponse, error) {
+	v, err := a.cached(cachedKey("GetAsset", asset_id), opts, func() ([]interface{}, error) {
+		res, err := a.API.GetAsset(asset_id, opts...)
+		return []interface{}{res}, err
+	})
+	return v[0].(AssetResponse), err
+}
+
+func (a *CachingAPI) GetAssets(params AssetsRequestParams, opts ...RequestOption) (AssetsResponse, error) {
+	v, err := a.cached(cachedKey("GetAssets", params), opts, func() ([]interface{}, error) {
+		res, err := a.API.GetAssets(params, opts...)
+		return []interface{}{res}, err
+	})
+	return v[0].(AssetsResponse), err
+}
+
+func (a *CachingAPI) GetAssetsByIDs(ids []string, opts ...RequestOption) (map[string]Asset, []string, error) {
+	v, err := a.cached(cachedKey("GetAssetsByIDs", ids), opts, func() ([]interface{}, error) {
+		assets, missing, err := a.API.GetAssetsByIDs(ids, opts...)
+		return []interface{}{assets, missing}, err
+	})
+	return v[0].(map[string]Asset), v[1].([]string), err
+}
+
+func (a *CachingAPI) GetAssetLog(asset_id string, params LogRequestParams, opts ...RequestOption) (AssetLogResponse, error) {
+	v, err := a.cached(cachedKey("GetAssetLog", asset_id, params), opts, func() ([]interface{}, error) {
+		res, err := a.API.GetAssetLog(asset_id, params, opts...)
+		return []interface{}{res}, err
+	})
+	return v[0].(AssetLogResponse), err
+}
+
+func (a *CachingAPI) GetAssetSales(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) (SalesResponse, error) {
+	v, err := a.cached(cachedKey("GetAssetSales", asset_id, params), opts, func() ([]interface{}, error) {
+		res, err := a.API.GetAssetSales(asset_id, params, opts...)
+		return []interface{}{res}, err
+	})
+	return v[0].(SalesResponse), err
+}
@@ -0,0 +1,220 @@
+package eos_contract_api_client
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Keep Client and the decorators in sync with API.
+var (
+	_ API = (*Client)(nil)
+	_ API = (*LoggingAPI)(nil)
+	_ API = (*MetricsAPI)(nil)
+	_ API = (*CachingAPI)(nil)
+	_ API = (*stubAPI)(nil)
+)
+
+// stubAPI counts calls and returns err from every method.
+type stubAPI struct {
+	calls map[string]int
+	err   error
+}
+
+func (s *stubAPI) call(method string) {
+	if s.calls == nil {
+		s.calls = map[string]int{}
+	}
+	s.calls[method]++
+}
+
+func (s *stubAPI) GetHealth(opts ...RequestOption) (Health, error) {
+	s.call("GetHealth")
+	h := Health{Data: HealthData{Version: "1.0.0"}}
+	h.HTTPStatusCode = 200
+	return h, s.err
+}
+
+func (s *stubAPI) GetAsset(asset_id string, opts ...RequestOption) (AssetResponse, error) {
+	s.call("GetAsset")
+	return AssetResponse{Data: Asset{ID: asset_id}}, s.err
+}
+
+func (s *stubAPI) GetAssets(params AssetsRequestParams, opts ...RequestOption) (AssetsResponse, error) {
+	s.call("GetAssets")
+	res := AssetsResponse{Data: []Asset{{Owner: params.Owner}}}
+	res.HTTPStatusCode = 200
+	return res, s.err
+}
+
+func (s *stubAPI) GetAssetsByIDs(ids []string, opts ...RequestOption) (map[string]Asset, []string, error) {
+	s.call("GetAssetsByIDs")
+	return map[string]Asset{}, ids, s.err
+}
+
+func (s *stubAPI) GetAssetLog(asset_id string, params LogRequestParams, opts ...RequestOption) (AssetLogResponse, error) {
+	s.call("GetAssetLog")
+	return AssetLogResponse{}, s.err
+}
+
+func (s *stubAPI) GetAssetSales(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) (SalesResponse, error) {
+	s.call("GetAssetSales")
+	return SalesResponse{}, s.err
+}
+
+// callAll calls every method of api once.
+func callAll(api API) {
+	api.GetHealth()
+	api.GetAsset("1099667509880")
+	api.GetAssets(AssetsRequestParams{Owner: "farmersworld"})
+	api.GetAssetsByIDs([]string{"1", "2"})
+	api.GetAssetLog("1099667509880", LogRequestParams{})
+	api.GetAssetSales("1099667509880", AssetSalesRequestParams{})
+}
+
+func TestLoggingAPI(t *testing.T) {
+	logger := &testLogger{}
+	stub := &stubAPI{}
+
+	callAll(NewLoggingAPI(stub, logger))
+
+	require.Len(t, logger.entries, 6)
+	assert.Equal(t, LogInfo, logger.entries[0].level)
+	assert.Equal(t, "call", logger.entries[0].msg)
+	assert.Equal(t, "GetHealth", logger.entries[0].fields["method"])
+	assert.Equal(t, "GetAssetSales", logger.entries[5].fields["method"])
+
+	stub.err = &APIError{StatusCode: 404}
+	NewLoggingAPI(stub, logger).GetAsset("1")
+	assert.Equal(t, LogWarn, logger.entries[6].level)
+	assert.Equal(t, "API Error: 404 Not Found", logger.entries[6].fields["error"])
+
+	stub.err = errors.New("connection refused")
+	NewLoggingAPI(stub, logger).GetAsset("1")
+	assert.Equal(t, LogError, logger.entries[7].level)
+
+	// Urls in transport errors are redacted.
+	stub.err = &url.Error{Op: "Get", URL: "http://localhost/health?api_key=SECRET", Err: errors.New("connection refused")}
+	NewLoggingAPI(stub, logger).GetHealth()
+	assert.Equal(t, `Get "http://localhost/health?api_key=%5BREDACTED%5D": connection refused`, logger.entries[8].fields["error"])
+}
+
+func TestMetricsAPI(t *testing.T) {
+	m := NewMetrics()
+	stub := &stubAPI{}
+
+	api := NewMetricsAPI(stub, m)
+	callAll(api)
+
+	stub.err = &APIError{StatusCode: 429}
+	api.GetAssets(AssetsRequestParams{})
+
+	out := bytes.Buffer{}
+	_, err := m.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), `eos_contract_api_client_requests_total{method="GET",endpoint="GetAssets",code="200"} 1`)
+	assert.Contains(t, out.String(), `eos_contract_api_client_requests_total{method="GET",endpoint="GetAssets",code="429"} 1`)
+	assert.Contains(t, out.String(), `eos_contract_api_client_requests_total{method="GET",endpoint="GetAssetsByIDs",code="200"} 1`)
+	assert.Contains(t, out.String(), `eos_contract_api_client_errors_total{endpoint="GetAssets",kind="rate_limited"} 1`)
+}
+
+func TestCachingAPI(t *testing.T) {
+	stub := &stubAPI{}
+	api := NewCachingAPI(stub, time.Minute)
+
+	callAll(api)
+	callAll(api)
+
+	assert.Equal(t, map[string]int{
+		"GetHealth":      1,
+		"GetAsset":       1,
+		"GetAssets":      1,
+		"GetAssetsByIDs": 1,
+		"GetAssetLog":    1,
+		"GetAssetSales":  1,
+	}, stub.calls)
+
+	h, err := api.GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", h.Data.Version)
+
+	_, missing, err := api.GetAssetsByIDs([]string{"1", "2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, missing)
+
+	// Different arguments are cached separately.
+	res, err := api.GetAssets(AssetsRequestParams{Owner: "someone"})
+	require.NoError(t, err)
+	assert.Equal(t, "someone", res.Data[0].Owner)
+	assert.Equal(t, 2, stub.calls["GetAssets"])
+
+	// Errors are not cached.
+	stub.err = ErrServerUnavailable
+	_, err = api.GetAsset("2")
+	assert.Error(t, err)
+	_, err = api.GetAsset("2")
+	assert.Error(t, err)
+	assert.Equal(t, 3, stub.calls["GetAsset"])
+
+	// Expired results are fetched again.
+	stub.err = nil
+	api.TTL = time.Millisecond
+	api.GetAssetSales("1", AssetSalesRequestParams{})
+	time.Sleep(5 * time.Millisecond)
+	api.GetAssetSales("1", AssetSalesRequestParams{})
+	assert.Equal(t, 3, stub.calls["GetAssetSales"])
+}
+
+func TestCachingAPI_Options(t *testing.T) {
+	stub := &stubAPI{}
+	api := NewCachingAPI(stub, time.Minute)
+
+	api.GetAsset("1")
+	api.GetAsset("1", WithRequestHeader("X-Request-Id", "1"))
+	assert.Equal(t, 1, stub.calls["GetAsset"])
+
+	// Options that ask for a fresh result skip the lookup.
+	api.GetAsset("1", WithNoCache())
+	api.GetAsset("1", WithMinBlock(100))
+	api.GetAsset("1", WithMaxLag(time.Second))
+	assert.Equal(t, 4, stub.calls["GetAsset"])
+
+	api.GetAsset("1")
+	assert.Equal(t, 4, stub.calls["GetAsset"])
+}
+
+func TestCachingAPI_Sweep(t *testing.T) {
+	stub := &stubAPI{}
+	api := NewCachingAPI(stub, time.Millisecond)
+
+	api.GetAsset("1")
+	api.GetAsset("2")
+	time.Sleep(5 * time.Millisecond)
+
+	// Storing a result removes the expired ones.
+	api.GetAsset("3")
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	assert.Len(t, api.results, 1)
+	assert.Contains(t, api.results, cachedKey("GetAsset", "3"))
+}
+
+func TestDecorators_Chain(t *testing.T) {
+	stub := &stubAPI{}
+	logger := &testLogger{}
+
+	var api API = NewLoggingAPI(NewCachingAPI(stub, time.Minute), logger)
+
+	api.GetHealth()
+	api.GetHealth()
+
+	assert.Equal(t, 1, stub.calls["GetHealth"])
+	assert.Len(t, logger.entries, 2)
+}
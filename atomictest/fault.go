@@ -0,0 +1,219 @@
+package atomictest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault is a failure injected by FaultTransport.
+type Fault int
+
+const (
+	// FaultNone sends the request unchanged.
+	FaultNone Fault = iota
+	// FaultReset fails with a connection reset.
+	FaultReset
+	// FaultTruncate cuts the response body in half.
+	FaultTruncate
+	// FaultMalformedJSON responds with invalid json.
+	FaultMalformedJSON
+	// FaultContentType responds with an html page.
+	FaultContentType
+	// FaultRateLimit responds with 429 Too Many Requests.
+	FaultRateLimit
+	// FaultServerError responds with 500 Internal Server Error.
+	FaultServerError
+	// FaultUnavailable responds with 503 Service Unavailable.
+	FaultUnavailable
+	// FaultFailure responds with 200 and a success:false envelope.
+	FaultFailure
+)
+
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "none"
+	case FaultReset:
+		return "reset"
+	case FaultTruncate:
+		return "truncate"
+	case FaultMalformedJSON:
+		return "malformed_json"
+	case FaultContentType:
+		return "content_type"
+	case FaultRateLimit:
+		return "rate_limit"
+	case FaultServerError:
+		return "server_error"
+	case FaultUnavailable:
+		return "unavailable"
+	case FaultFailure:
+		return "failure"
+	}
+	return fmt.Sprintf("Fault(%d)", int(f))
+}
+
+// FaultTransport is an http.RoundTripper that injects faults into
+// requests. The first requests get the faults in Script, in order,
+// later requests get a fault chosen with Probabilities.
+// Use it with eos_contract_api_client.WithTransport.
+type FaultTransport struct {
+	// Transport sends requests that are not replaced by a fault,
+	// http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+
+	// Latency is added to every request.
+	Latency time.Duration
+
+	Script []Fault
+
+	// Probabilities maps a fault to the probability of it being
+	// injected, the sum should not exceed 1.
+	Probabilities map[Fault]float64
+
+	// Rand is used to choose faults, seed it for repeatable runs.
+	Rand *rand.Rand
+
+	mu       sync.Mutex
+	injected []Fault
+}
+
+// NewFaultTransport returns a FaultTransport sending requests with next
+// and choosing faults with a random source seeded with seed.
+func NewFaultTransport(next http.RoundTripper, seed int64) *FaultTransport {
+	return &FaultTransport{Transport: next, Rand: rand.New(rand.NewSource(seed))}
+}
+
+// Injected returns the fault of every request so far.
+func (t *FaultTransport) Injected() []Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Fault(nil), t.injected...)
+}
+
+func (t *FaultTransport) next() Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f := FaultNone
+	if n := len(t.injected); n < len(t.Script) {
+		f = t.Script[n]
+	} else if len(t.Probabilities) > 0 {
+		if t.Rand == nil {
+			t.Rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+
+		// Iterate in a fixed order so a seeded Rand is repeatable.
+		faults := make([]Fault, 0, len(t.Probabilities))
+		for k := range t.Probabilities {
+			faults = append(faults, k)
+		}
+		sort.Slice(faults, func(i, j int) bool { return faults[i] < faults[j] })
+
+		p := t.Rand.Float64()
+		for _, k := range faults {
+			if p < t.Probabilities[k] {
+				f = k
+				break
+			}
+			p -= t.Probabilities[k]
+		}
+	}
+
+	t.injected = append(t.injected, f)
+	return f
+}
+
+func response(r *http.Request, status int, contentType string, body string) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+// truncatedBody returns the first half of a body, then io.ErrUnexpectedEOF.
+type truncatedBody struct {
+	io.Reader
+}
+
+func (b truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (b truncatedBody) Close() error {
+	return nil
+}
+
+func (t *FaultTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	f := t.next()
+
+	if t.Latency > 0 {
+		timer := time.NewTimer(t.Latency)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+			return nil, r.Context().Err()
+		}
+	}
+
+	switch f {
+	case FaultReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case FaultMalformedJSON:
+		return response(r, http.StatusOK, "application/json", `{"success":true,"data":[{"asset_id":`), nil
+	case FaultContentType:
+		return response(r, http.StatusOK, "text/html", "<html><body>Bad Gateway</body></html>"), nil
+	case FaultRateLimit:
+		resp := response(r, http.StatusTooManyRequests, "application/json", `{"success":false,"message":"Rate limit exceeded"}`)
+		resp.Header.Set("Retry-After", "0")
+		return resp, nil
+	case FaultServerError:
+		return response(r, http.StatusInternalServerError, "application/json", `{"success":false,"message":"Internal Server Error"}`), nil
+	case FaultUnavailable:
+		return response(r, http.StatusServiceUnavailable, "text/plain", "Service Unavailable"), nil
+	case FaultFailure:
+		return response(r, http.StatusOK, "application/json", `{"success":false,"message":"Injected failure"}`), nil
+	}
+
+	next := t.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(r)
+	if err != nil || f != FaultTruncate {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = truncatedBody{bytes.NewReader(body[:len(body)/2])}
+	return resp, nil
+}
@@ -0,0 +1,136 @@
+package atomictest
+
+import (
+	"encoding/json"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	eos "github.com/eosswedenorg-go/eos-contract-api-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFault_String(t *testing.T) {
+	assert.Equal(t, "none", FaultNone.String())
+	assert.Equal(t, "malformed_json", FaultMalformedJSON.String())
+	assert.Equal(t, "Fault(100)", Fault(100).String())
+}
+
+func TestFaultTransport_Script(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddAssets(eos.Asset{ID: "1099667509880", Name: "Wood"})
+
+	ft := NewFaultTransport(nil, 1)
+	ft.Script = []Fault{
+		FaultReset,
+		FaultTruncate,
+		FaultMalformedJSON,
+		FaultContentType,
+		FaultRateLimit,
+		FaultServerError,
+		FaultUnavailable,
+		FaultFailure,
+		FaultNone,
+	}
+
+	client := s.Client(eos.WithTransport(ft))
+
+	_, err := client.GetAsset("1099667509880")
+	assert.ErrorIs(t, err, syscall.ECONNRESET)
+
+	_, err = client.GetAsset("1099667509880")
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = client.GetAsset("1099667509880")
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+
+	_, err = client.GetAsset("1099667509880")
+	assert.ErrorIs(t, err, eos.ErrBadContentType)
+
+	_, err = client.GetAsset("1099667509880")
+	assert.ErrorIs(t, err, eos.ErrRateLimited)
+
+	_, err = client.GetAsset("1099667509880")
+	assert.EqualError(t, err, "API Error: Internal Server Error")
+
+	_, err = client.GetAsset("1099667509880")
+	assert.ErrorIs(t, err, eos.ErrServerUnavailable)
+
+	a, err := client.GetAsset("1099667509880")
+	require.NoError(t, err)
+	assert.False(t, a.Success)
+
+	a, err = client.GetAsset("1099667509880")
+	require.NoError(t, err)
+	assert.True(t, a.Success)
+	assert.Equal(t, "Wood", a.Data.Name)
+
+	assert.Equal(t, ft.Script, ft.Injected())
+}
+
+func TestFaultTransport_Retry(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	ft := NewFaultTransport(nil, 1)
+	ft.Script = []Fault{FaultReset, FaultUnavailable, FaultRateLimit}
+
+	client := s.Client(eos.WithTransport(ft), eos.WithRetry(eos.RetryPolicy{MaxRetries: 3}))
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, []Fault{FaultReset, FaultUnavailable, FaultRateLimit, FaultNone}, ft.Injected())
+}
+
+func TestFaultTransport_Probabilities(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	run := func(seed int64) []Fault {
+		ft := NewFaultTransport(nil, seed)
+		ft.Probabilities = map[Fault]float64{FaultUnavailable: 0.3, FaultReset: 0.1}
+
+		client := s.Client(eos.WithTransport(ft))
+		for i := 0; i < 500; i++ {
+			client.GetHealth()
+		}
+		return ft.Injected()
+	}
+
+	faults := run(42)
+
+	counts := map[Fault]int{}
+	for _, f := range faults {
+		counts[f]++
+	}
+
+	assert.Len(t, faults, 500)
+	assert.InDelta(t, 150, counts[FaultUnavailable], 40)
+	assert.InDelta(t, 50, counts[FaultReset], 25)
+	assert.Equal(t, 500, counts[FaultNone]+counts[FaultUnavailable]+counts[FaultReset])
+
+	// The same seed injects the same faults.
+	assert.Equal(t, faults, run(42))
+}
+
+func TestFaultTransport_Latency(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	ft := NewFaultTransport(nil, 1)
+	ft.Latency = 50 * time.Millisecond
+
+	client := s.Client(eos.WithTransport(ft))
+
+	start := time.Now()
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), ft.Latency)
+
+	_, err = client.GetHealth(eos.WithRequestTimeout(10 * time.Millisecond))
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package atomictest
+
+import (
+	"strconv"
+	"strings"
+
+	eos "github.com/eosswedenorg-go/eos-contract-api-client"
+)
+
+var assetSortKeys = map[string]func(eos.Asset) string{
+	"asset_id":      func(a eos.Asset) string { return a.ID },
+	"minted":        func(a eos.Asset) string { return a.MintedAtTime },
+	"updated":       func(a eos.Asset) string { return a.UpdatedAtTime },
+	"transferred":   func(a eos.Asset) string { return a.TransferedAtTime },
+	"template_mint": func(a eos.Asset) string { return a.TemplateMint },
+	"name":          func(a eos.Asset) string { return a.Name },
+}
+
+type assetFilter struct {
+	q query
+
+	ids                 []string
+	collectionWhitelist []string
+	collectionBlacklist []string
+	templateWhitelist   []string
+	templateBlacklist   []string
+
+	transferable    *bool
+	burnable        *bool
+	burned          *bool
+	hasBackedTokens *bool
+
+	before int
+	after  int
+}
+
+func newAssetFilter(q query) (*assetFilter, error) {
+	var err error
+
+	f := &assetFilter{
+		q:                   q,
+		ids:                 q.list("ids"),
+		collectionWhitelist: q.list("collection_whitelist"),
+		collectionBlacklist: q.list("collection_blacklist"),
+		templateWhitelist:   q.list("template_whitelist"),
+		templateBlacklist:   q.list("template_blacklist"),
+	}
+
+	bools := map[string]**bool{
+		"is_transferable":    &f.transferable,
+		"is_burnable":        &f.burnable,
+		"burned":             &f.burned,
+		"has_backend_tokens": &f.hasBackedTokens,
+	}
+
+	for name, b := range bools {
+		if *b, err = q.bool(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.before, err = q.int("before", 0); err != nil {
+		return nil, err
+	}
+
+	if f.after, err = q.int("after", 0); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func matchBool(b *bool, v bool) bool {
+	return b == nil || *b == v
+}
+
+// matchName reports if the name in data contains match, ignoring case.
+func matchName(data map[string]interface{}, match string) bool {
+	if len(match) < 1 {
+		return true
+	}
+	name, _ := data["name"].(string)
+	return strings.Contains(strings.ToLower(name), strings.ToLower(match))
+}
+
+func (f *assetFilter) match(a eos.Asset) bool {
+	q := f.q
+	minted, _ := strconv.Atoi(a.MintedAtTime)
+
+	return q.is("collection_name", a.Collection.CollectionName) &&
+		q.is("schema_name", a.Schema.Name) &&
+		q.is("template_id", a.Template.ID) &&
+		q.is("owner", a.Owner) &&
+		(len(f.ids) < 1 || contains(f.ids, a.ID)) &&
+		(len(f.collectionWhitelist) < 1 || contains(f.collectionWhitelist, a.Collection.CollectionName)) &&
+		!contains(f.collectionBlacklist, a.Collection.CollectionName) &&
+		(len(f.templateWhitelist) < 1 || contains(f.templateWhitelist, a.Template.ID)) &&
+		!contains(f.templateBlacklist, a.Template.ID) &&
+		matchBool(f.transferable, a.IsTransferable) &&
+		matchBool(f.burnable, a.IsBurnable) &&
+		matchBool(f.burned, len(a.BurnedByAccount) > 0) &&
+		matchBool(f.hasBackedTokens, len(a.BackedTokens) > 0) &&
+		(f.before < 1 || minted < f.before) &&
+		(f.after < 1 || minted > f.after) &&
+		(len(q.Get("match")) < 1 || strings.Contains(strings.ToLower(a.Name), strings.ToLower(q.Get("match")))) &&
+		matchName(a.ImmutableData, q.Get("match_immutable_name")) &&
+		matchName(a.MutableData, q.Get("match_mutable_name"))
+}
@@ -0,0 +1,136 @@
+package atomictest
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type query struct {
+	url.Values
+}
+
+func invalid(name string) error {
+	return errors.New("Invalid value for parameter " + name)
+}
+
+// int returns the integer parameter name, or def if it is not set.
+func (q query) int(name string, def int) (int, error) {
+	v := q.Get(name)
+	if len(v) < 1 {
+		return def, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, invalid(name)
+	}
+	return i, nil
+}
+
+// bool returns the boolean parameter name, or nil if it is not set.
+func (q query) bool(name string) (*bool, error) {
+	v := q.Get(name)
+	if len(v) < 1 {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, invalid(name)
+	}
+	return &b, nil
+}
+
+// list returns the values of name, given either as a comma separated
+// list or as repeated parameters.
+func (q query) list(name string) []string {
+	out := []string{}
+	for _, v := range q.Values[name] {
+		for _, s := range strings.Split(v, ",") {
+			if len(s) > 0 {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// is reports if name is not set or equal to value.
+func (q query) is(name string, value string) bool {
+	v := q.Get(name)
+	return len(v) < 1 || v == value
+}
+
+// bounds reports if id is within lower_bound and upper_bound.
+func (q query) bounds(id string) bool {
+	lower, upper := q.Get("lower_bound"), q.Get("upper_bound")
+	return (len(lower) < 1 || !less(id, lower)) && (len(upper) < 1 || !less(upper, id))
+}
+
+func (q query) page() (int, int, error) {
+	page, err := q.int("page", 1)
+	if err != nil || page < 1 {
+		return 0, 0, invalid("page")
+	}
+
+	limit, err := q.int("limit", DefaultLimit)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, 0, invalid("limit")
+	}
+	return page, limit, nil
+}
+
+func (q query) order() (string, error) {
+	switch o := q.Get("order"); o {
+	case "":
+		return "desc", nil
+	case "asc", "desc":
+		return o, nil
+	}
+	return "", invalid("order")
+}
+
+// pageRange returns the slice bounds of page in n items.
+func pageRange(n int, page int, limit int) (int, int) {
+	lo := (page - 1) * limit
+	if lo > n {
+		lo = n
+	}
+
+	hi := lo + limit
+	if hi > n {
+		hi = n
+	}
+	return lo, hi
+}
+
+func numeric(s string) bool {
+	if len(s) < 1 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// less compares numeric strings as numbers and other strings as text.
+func less(a string, b string) bool {
+	if numeric(a) && numeric(b) && len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+// Package atomictest provides a fake AtomicAssets API server for tests.
+//
+// The server is seeded with fixtures and implements the filtering,
+// sorting, pagination and errors of the endpoints used by the client.
+// FaultTransport injects failures into the requests of a client.
+package atomictest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	eos "github.com/eosswedenorg-go/eos-contract-api-client"
+)
+
+// MaxLimit is the largest limit accepted by the server.
+const MaxLimit = 1000
+
+// DefaultLimit is the limit used when none is given.
+const DefaultLimit = 100
+
+type failure struct {
+	path    string
+	status  int
+	message string
+}
+
+// Server is a fake AtomicAssets API server.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	health      eos.HealthData
+	assets      []eos.Asset
+	collections map[string]eos.Collection
+	templates   map[string]eos.Template
+	logs        map[string][]eos.Log
+	sales       map[string][]eos.AssetSale
+	failures    []failure
+}
+
+// NewServer starts a new Server, it must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		health: eos.HealthData{
+			Version:  "1.0.0",
+			Postgres: eos.PostgresHealth{Status: "OK"},
+			Redis:    eos.RedisHealth{Status: "OK"},
+			Chain:    eos.ChainHealth{Status: "OK"},
+		},
+		collections: make(map[string]eos.Collection),
+		templates:   make(map[string]eos.Template),
+		logs:        make(map[string][]eos.Log),
+		sales:       make(map[string][]eos.AssetSale),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Client returns a client for the server.
+func (s *Server) Client(opts ...eos.Option) *eos.Client {
+	return eos.New(s.URL, opts...)
+}
+
+// SetHealth sets the data returned by "/health".
+func (s *Server) SetHealth(h eos.HealthData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = h
+}
+
+// AddAssets adds assets, an asset with the same id is replaced.
+func (s *Server) AddAssets(assets ...eos.Asset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+next:
+	for _, a := range assets {
+		for i := range s.assets {
+			if s.assets[i].ID == a.ID {
+				s.assets[i] = a
+				continue next
+			}
+		}
+		s.assets = append(s.assets, a)
+	}
+}
+
+// AddCollections adds collections, they are included in the
+// assets with the same collection name.
+func (s *Server) AddCollections(collections ...eos.Collection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range collections {
+		s.collections[c.CollectionName] = c
+	}
+}
+
+// AddTemplates adds templates, they are included in the
+// assets with the same template id.
+func (s *Server) AddTemplates(templates ...eos.Template) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range templates {
+		s.templates[t.ID] = t
+	}
+}
+
+// AddLogs adds logs for an asset.
+func (s *Server) AddLogs(assetID string, logs ...eos.Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs[assetID] = append(s.logs[assetID], logs...)
+}
+
+// AddSales adds sales for an asset.
+func (s *Server) AddSales(assetID string, sales ...eos.AssetSale) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sales[assetID] = append(s.sales[assetID], sales...)
+}
+
+// FailNext makes the next request to path fail with status and message.
+// Calling it several times fails several requests.
+func (s *Server) FailNext(path string, status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{path, status, message})
+}
+
+func (s *Server) write(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) fail(w http.ResponseWriter, status int, message string) {
+	s.write(w, status, map[string]interface{}{"success": false, "message": message})
+}
+
+func (s *Server) ok(w http.ResponseWriter, data interface{}) {
+	s.write(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"data":       data,
+		"query_time": time.Now().UnixNano() / 1e6,
+	})
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.failures {
+		if f.path == r.URL.Path {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			s.fail(w, f.status, f.message)
+			return
+		}
+	}
+
+	if r.Method != http.MethodGet {
+		s.fail(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	q := query{r.URL.Query()}
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.URL.Path == "/health":
+		s.ok(w, s.health)
+	case r.URL.Path == "/atomicassets/v1/assets":
+		s.serveAssets(w, q)
+	case len(segments) == 4 && segments[0] == "atomicassets" && segments[2] == "assets":
+		s.serveAsset(w, segments[3])
+	case len(segments) == 5 && segments[0] == "atomicassets" && segments[2] == "assets" && segments[4] == "logs":
+		s.serveLogs(w, segments[3], q)
+	case len(segments) == 5 && segments[0] == "atomicmarket" && segments[2] == "assets" && segments[4] == "sales":
+		s.serveSales(w, segments[3], q)
+	default:
+		s.fail(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+// asset returns a with the seeded collection and template.
+func (s *Server) asset(a eos.Asset) eos.Asset {
+	if c, ok := s.collections[a.Collection.CollectionName]; ok {
+		a.Collection = c
+	}
+	if t, ok := s.templates[a.Template.ID]; ok {
+		a.Template = t
+	}
+	return a
+}
+
+func (s *Server) serveAsset(w http.ResponseWriter, id string) {
+	for _, a := range s.assets {
+		if a.ID == id {
+			s.ok(w, s.asset(a))
+			return
+		}
+	}
+	s.fail(w, http.StatusNotFound, "Asset not found")
+}
+
+func (s *Server) serveAssets(w http.ResponseWriter, q query) {
+	page, limit, err := q.page()
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := q.order()
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sortBy := q.Get("sort")
+	if len(sortBy) < 1 {
+		sortBy = "asset_id"
+	}
+
+	key, ok := assetSortKeys[sortBy]
+	if !ok {
+		s.fail(w, http.StatusBadRequest, "Invalid value for parameter sort")
+		return
+	}
+
+	f, err := newAssetFilter(q)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assets := []eos.Asset{}
+	for _, a := range s.assets {
+		a = s.asset(a)
+		if f.match(a) && (sortBy != "asset_id" || q.bounds(a.ID)) {
+			assets = append(assets, a)
+		}
+	}
+
+	sort.SliceStable(assets, func(i, j int) bool {
+		a, b := key(assets[i]), key(assets[j])
+		if order == "asc" {
+			return less(a, b)
+		}
+		return less(b, a)
+	})
+
+	lo, hi := pageRange(len(assets), page, limit)
+	s.ok(w, assets[lo:hi])
+}
+
+func (s *Server) serveLogs(w http.ResponseWriter, id string, q query) {
+	page, limit, err := q.page()
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := q.order()
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	whitelist, blacklist := q.list("action_whitelist"), q.list("action_blacklist")
+
+	logs := []eos.Log{}
+	for _, l := range s.logs[id] {
+		if (len(whitelist) < 1 || contains(whitelist, l.Name)) && !contains(blacklist, l.Name) {
+			logs = append(logs, l)
+		}
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if order == "asc" {
+			return less(logs[i].ID, logs[j].ID)
+		}
+		return less(logs[j].ID, logs[i].ID)
+	})
+
+	lo, hi := pageRange(len(logs), page, limit)
+	s.ok(w, logs[lo:hi])
+}
+
+func (s *Server) serveSales(w http.ResponseWriter, id string, q query) {
+	page, limit, err := q.page()
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := q.order()
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sales := []eos.AssetSale{}
+	for _, sale := range s.sales[id] {
+		if q.is("buyer", sale.Buyer) && q.is("seller", sale.Seller) && q.is("symbol", sale.TokenSymbol) {
+			sales = append(sales, sale)
+		}
+	}
+
+	sort.SliceStable(sales, func(i, j int) bool {
+		if order == "asc" {
+			return sales[i].BlockTime < sales[j].BlockTime
+		}
+		return sales[j].BlockTime < sales[i].BlockTime
+	})
+
+	lo, hi := pageRange(len(sales), page, limit)
+	s.ok(w, sales[lo:hi])
+}
@@ -0,0 +1,237 @@
+package atomictest
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	eos "github.com/eosswedenorg-go/eos-contract-api-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(assets []eos.Asset) []string {
+	out := []string{}
+	for _, a := range assets {
+		out = append(out, a.ID)
+	}
+	return out
+}
+
+func seed(s *Server) {
+	s.AddCollections(eos.Collection{CollectionName: "farmersworld", Name: "Farmers World", Author: "farmersworld"})
+	s.AddTemplates(eos.Template{ID: "260676", MaxSupply: "0", IssuedSupply: "3"})
+
+	s.AddAssets(
+		eos.Asset{ID: "1099667509880", Owner: "alice", Name: "Wood", TemplateMint: "1", MintedAtTime: "1669043479000", IsTransferable: true,
+			Collection: eos.Collection{CollectionName: "farmersworld"}, Template: eos.Template{ID: "260676"}},
+		eos.Asset{ID: "1099667509881", Owner: "bob", Name: "Stone", TemplateMint: "2", MintedAtTime: "1669043480000",
+			Collection: eos.Collection{CollectionName: "farmersworld"}, Template: eos.Template{ID: "260676"}},
+		eos.Asset{ID: "999999", Owner: "alice", Name: "Old wood", TemplateMint: "3", MintedAtTime: "1569043479000", BurnedByAccount: "alice",
+			Collection: eos.Collection{CollectionName: "alien.worlds"}},
+	)
+}
+
+func TestServer_Health(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.SetHealth(eos.HealthData{Version: "2.0.0", Chain: eos.ChainHealth{Status: "OK", HeadBlock: 1234}})
+
+	h, err := s.Client().GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", h.Data.Version)
+	assert.Equal(t, int64(1234), h.Data.Chain.HeadBlock)
+	assert.NotZero(t, h.QueryTime)
+}
+
+func TestServer_Asset(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	seed(s)
+
+	client := s.Client()
+
+	a, err := client.GetAsset("1099667509880")
+	require.NoError(t, err)
+	assert.Equal(t, "Wood", a.Data.Name)
+	assert.Equal(t, "Farmers World", a.Data.Collection.Name)
+	assert.Equal(t, "3", a.Data.Template.IssuedSupply)
+
+	_, err = client.GetAsset("1")
+	assert.ErrorIs(t, err, eos.ErrNotFound)
+	assert.EqualError(t, err, "API Error: Asset not found")
+}
+
+func TestServer_Assets(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	seed(s)
+
+	client := s.Client()
+
+	tests := []struct {
+		name     string
+		params   eos.AssetsRequestParams
+		expected []string
+	}{
+		{"Default", eos.AssetsRequestParams{}, []string{"1099667509881", "1099667509880", "999999"}},
+		{"Ascending", eos.AssetsRequestParams{Order: "asc"}, []string{"999999", "1099667509880", "1099667509881"}},
+		{"Owner", eos.AssetsRequestParams{Owner: "alice"}, []string{"1099667509880", "999999"}},
+		{"Collection", eos.AssetsRequestParams{CollectionName: "farmersworld"}, []string{"1099667509881", "1099667509880"}},
+		{"CollectionBlacklist", eos.AssetsRequestParams{CollectionBlacklist: []string{"farmersworld"}}, []string{"999999"}},
+		{"Template", eos.AssetsRequestParams{TemplateID: 260676, Order: "asc"}, []string{"1099667509880", "1099667509881"}},
+		{"Burned", eos.AssetsRequestParams{Burned: true}, []string{"999999"}},
+		{"Transferable", eos.AssetsRequestParams{IsTransferable: true}, []string{"1099667509880"}},
+		{"Match", eos.AssetsRequestParams{Match: "wood"}, []string{"1099667509880", "999999"}},
+		{"IDs", eos.AssetsRequestParams{IDs: "999999,1099667509881"}, []string{"1099667509881", "999999"}},
+		{"Bounds", eos.AssetsRequestParams{LowerBound: "1000000", UpperBound: "1099667509880"}, []string{"1099667509880"}},
+		{"After", eos.AssetsRequestParams{After: 1669043479000}, []string{"1099667509881"}},
+		{"SortName", eos.AssetsRequestParams{Sort: "name", Order: "asc"}, []string{"999999", "1099667509881", "1099667509880"}},
+		{"SortMint", eos.AssetsRequestParams{Sort: "template_mint"}, []string{"999999", "1099667509881", "1099667509880"}},
+		{"Page", eos.AssetsRequestParams{Page: 2, Limit: 2}, []string{"999999"}},
+		{"PageEnd", eos.AssetsRequestParams{Page: 3, Limit: 2}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.GetAssets(tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(res.Data))
+		})
+	}
+}
+
+func TestServer_AssetsInvalid(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	client := s.Client()
+
+	tests := []struct {
+		params  eos.AssetsRequestParams
+		message string
+	}{
+		{eos.AssetsRequestParams{Limit: MaxLimit + 1}, "API Error: Invalid value for parameter limit"},
+		{eos.AssetsRequestParams{Page: -1}, "API Error: Invalid value for parameter page"},
+		{eos.AssetsRequestParams{Order: "up"}, "API Error: Invalid value for parameter order"},
+		{eos.AssetsRequestParams{Sort: "color"}, "API Error: Invalid value for parameter sort"},
+	}
+
+	for _, tt := range tests {
+		_, err := client.GetAssets(tt.params)
+		assert.EqualError(t, err, tt.message)
+	}
+}
+
+func TestServer_Crawl(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	expected := []string{}
+	for i := 1; i <= 25; i++ {
+		id := strconv.Itoa(1000 + i)
+		s.AddAssets(eos.Asset{ID: id})
+		expected = append(expected, id)
+	}
+
+	client := s.Client()
+
+	got := []string{}
+	it := client.AssetsIter(eos.AssetsRequestParams{Limit: 10, Order: "asc"})
+	for it.Next() {
+		got = append(got, it.Asset().ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, expected, got)
+
+	got = []string{}
+	cr := client.CrawlAssets(eos.AssetsRequestParams{Limit: 7}, "")
+	for cr.Next() {
+		got = append(got, cr.Asset().ID)
+	}
+	require.NoError(t, cr.Err())
+	assert.Equal(t, expected, got)
+
+	assets, missing, err := client.GetAssetsByIDs([]string{"1001", "1025", "9999"})
+	require.NoError(t, err)
+	assert.Len(t, assets, 2)
+	assert.Equal(t, []string{"9999"}, missing)
+}
+
+func TestServer_Logs(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.AddLogs("1099667509880",
+		eos.Log{ID: "1", Name: "logmint"},
+		eos.Log{ID: "2", Name: "logtransfer"},
+		eos.Log{ID: "10", Name: "logburnasset"},
+	)
+
+	client := s.Client()
+
+	logs, err := client.GetAssetLog("1099667509880", eos.LogRequestParams{})
+	require.NoError(t, err)
+	require.Len(t, logs.Data, 3)
+	assert.Equal(t, "10", logs.Data[0].ID)
+
+	logs, err = client.GetAssetLog("1099667509880", eos.LogRequestParams{Order: eos.SortAscending, ActionBlacklist: "logmint"})
+	require.NoError(t, err)
+	require.Len(t, logs.Data, 2)
+	assert.Equal(t, "logtransfer", logs.Data[0].Name)
+
+	logs, err = client.GetAssetLog("1099667509880", eos.LogRequestParams{ActionWhitelist: "logmint,logburnasset", Limit: 1, Page: 2})
+	require.NoError(t, err)
+	require.Len(t, logs.Data, 1)
+	assert.Equal(t, "logmint", logs.Data[0].Name)
+}
+
+func TestServer_Sales(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.AddSales("1099667509880",
+		eos.AssetSale{ID: "1", Seller: "alice", Buyer: "bob", TokenSymbol: "WAX", BlockTime: 1},
+		eos.AssetSale{ID: "2", Seller: "bob", Buyer: "carol", TokenSymbol: "WAX", BlockTime: 2},
+		eos.AssetSale{ID: "3", Seller: "carol", Buyer: "alice", TokenSymbol: "TLM", BlockTime: 3},
+	)
+
+	client := s.Client()
+
+	sales, err := client.GetAssetSales("1099667509880", eos.AssetSalesRequestParams{Symbol: "WAX"})
+	require.NoError(t, err)
+	require.Len(t, sales.Data, 2)
+	assert.Equal(t, "2", sales.Data[0].ID)
+
+	sales, err = client.GetAssetSales("1099667509880", eos.AssetSalesRequestParams{Seller: "carol"})
+	require.NoError(t, err)
+	require.Len(t, sales.Data, 1)
+	assert.Equal(t, "alice", sales.Data[0].Buyer)
+
+	sales, err = client.GetAssetSales("1", eos.AssetSalesRequestParams{})
+	require.NoError(t, err)
+	assert.Empty(t, sales.Data)
+}
+
+func TestServer_FailNext(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.FailNext("/health", http.StatusServiceUnavailable, "Service unavailable")
+	s.FailNext("/health", http.StatusTooManyRequests, "Rate limit")
+
+	client := s.Client()
+
+	_, err := client.GetHealth()
+	assert.ErrorIs(t, err, eos.ErrServerUnavailable)
+
+	_, err = client.GetHealth()
+	assert.ErrorIs(t, err, eos.ErrRateLimited)
+
+	_, err = client.GetHealth()
+	require.NoError(t, err)
+
+	_, err = client.GetAssetLog("1", eos.LogRequestParams{}, eos.WithRequestHeader("X-Test", "1"))
+	require.NoError(t, err)
+}
@@ -0,0 +1,243 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit in a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all requests until the cool-down has passed.
+	CircuitOpen
+	// CircuitHalfOpen lets one trial request through at a time.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// CircuitOpenError is returned without sending the request
+// when the circuit for the base URL is open.
+type CircuitOpenError struct {
+	URL string
+
+	// Until is when the next trial request is let through. While a trial
+	// is in flight it is one cool down from now, when the next trial is
+	// let through if the current one fails.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s", e.URL)
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// DefaultCircuitFailure counts server errors and errors where
+// no response was received as failures.
+func DefaultCircuitFailure(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	var ctErr *ContentTypeError
+	return !errors.As(err, &ctErr)
+}
+
+type circuit struct {
+	state     CircuitState
+	failures  int
+	successes int
+	opened    time.Time
+	trial     bool
+}
+
+// CircuitBreaker stops sending requests to a base URL after it fails
+// FailureThreshold times in a row. After CoolDown a trial request is let
+// through, the circuit closes again after SuccessThreshold successful
+// trials and opens on the first failure.
+//
+// A CircuitBreaker can be shared by several clients, each base URL
+// has its own circuit.
+type CircuitBreaker struct {
+	// FailureThreshold defaults to 5.
+	FailureThreshold int
+
+	// SuccessThreshold defaults to 1.
+	SuccessThreshold int
+
+	// CoolDown defaults to 30 seconds.
+	CoolDown time.Duration
+
+	// IsFailure reports if err counts as a failure,
+	// DefaultCircuitFailure is used if nil.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called when the circuit for url changes state.
+	OnStateChange func(url string, from CircuitState, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func NewCircuitBreaker(failures int, coolDown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{FailureThreshold: failures, CoolDown: coolDown}
+}
+
+func (b *CircuitBreaker) failureThreshold() int {
+	if b.FailureThreshold > 0 {
+		return b.FailureThreshold
+	}
+	return 5
+}
+
+func (b *CircuitBreaker) successThreshold() int {
+	if b.SuccessThreshold > 0 {
+		return b.SuccessThreshold
+	}
+	return 1
+}
+
+func (b *CircuitBreaker) coolDown() time.Duration {
+	if b.CoolDown > 0 {
+		return b.CoolDown
+	}
+	return 30 * time.Second
+}
+
+func (b *CircuitBreaker) isFailure(err error) bool {
+	if b.IsFailure != nil {
+		return b.IsFailure(err)
+	}
+	return DefaultCircuitFailure(err)
+}
+
+// circuit returns the circuit for url, b.mu must be held.
+func (b *CircuitBreaker) circuit(url string) *circuit {
+	if b.circuits == nil {
+		b.circuits = make(map[string]*circuit)
+	}
+
+	cb, ok := b.circuits[url]
+	if !ok {
+		cb = &circuit{}
+		b.circuits[url] = cb
+	}
+	return cb
+}
+
+// State returns the state of the circuit for url.
+func (b *CircuitBreaker) State(url string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb := b.circuit(url)
+	if cb.state == CircuitOpen && time.Since(cb.opened) >= b.coolDown() {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
+
+func (b *CircuitBreaker) changed(url string, from CircuitState, to CircuitState) {
+	if b.OnStateChange != nil && from != to {
+		b.OnStateChange(url, from, to)
+	}
+}
+
+// allow returns an error if a request to url may not be sent,
+// trial is true if the request is a half-open trial.
+func (b *CircuitBreaker) allow(url string) (trial bool, err error) {
+	b.mu.Lock()
+	cb := b.circuit(url)
+	from := cb.state
+
+	if cb.state == CircuitOpen && time.Since(cb.opened) >= b.coolDown() {
+		cb.state, cb.successes = CircuitHalfOpen, 0
+	}
+
+	if cb.state == CircuitOpen {
+		err = &CircuitOpenError{URL: url, Until: cb.opened.Add(b.coolDown())}
+	} else if cb.state == CircuitHalfOpen && cb.trial {
+		err = &CircuitOpenError{URL: url, Until: time.Now().Add(b.coolDown())}
+	} else if cb.state == CircuitHalfOpen {
+		cb.trial, trial = true, true
+	}
+
+	to := cb.state
+	b.mu.Unlock()
+
+	b.changed(url, from, to)
+	return trial, err
+}
+
+// record updates the circuit for url with the result of a request.
+func (b *CircuitBreaker) record(url string, trial bool, err error) {
+	b.mu.Lock()
+	cb := b.circuit(url)
+	from := cb.state
+
+	if trial {
+		cb.trial = false
+	}
+
+	switch {
+	case cb.state == CircuitOpen:
+		// A request sent before the circuit opened.
+	case err != nil && b.isFailure(err):
+		cb.failures++
+		if trial || cb.failures >= b.failureThreshold() {
+			cb.state, cb.opened = CircuitOpen, time.Now()
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+	default:
+		cb.failures = 0
+		if trial {
+			cb.successes++
+			if cb.successes >= b.successThreshold() {
+				cb.state = CircuitClosed
+			}
+		}
+	}
+
+	to := cb.state
+	b.mu.Unlock()
+
+	b.changed(url, from, to)
+}
+
+func (c *Client) fetchBreaker(r *Request, query string, header http.Header) (*Response, error) {
+	if c.Breaker == nil {
+		return c.fetch(r, query, header)
+	}
+
+	trial, err := c.Breaker.allow(r.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.fetch(r, query, header)
+	c.Breaker.record(r.BaseURL, trial, err)
+	return resp, err
+}
@@ -0,0 +1,179 @@
+package eos_contract_api_client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// breakerServer responds with the status code stored in code.
+func breakerServer(code *int32) (*httptest.Server, *int32) {
+	calls := new(int32)
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(calls, 1)
+		res.Header().Add("Content-type", "application/json")
+		res.WriteHeader(int(atomic.LoadInt32(code)))
+		res.Write([]byte(`{"success":true}`))
+	}))
+	return srv, calls
+}
+
+type stateChange struct {
+	URL  string
+	From CircuitState
+	To   CircuitState
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	code := int32(http.StatusBadGateway)
+	srv, calls := breakerServer(&code)
+	defer srv.Close()
+
+	mu := sync.Mutex{}
+	changes := []stateChange{}
+
+	breaker := NewCircuitBreaker(3, 50*time.Millisecond)
+	breaker.OnStateChange = func(url string, from CircuitState, to CircuitState) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, stateChange{url, from, to})
+	}
+
+	client := New(srv.URL, WithCircuitBreaker(breaker), WithRetry(RetryPolicy{MaxRetries: 5}))
+
+	// The third failed attempt opens the circuit, which stops the retries.
+	_, err := client.GetHealth()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+	assert.Equal(t, CircuitOpen, breaker.State(srv.URL))
+
+	var openErr *CircuitOpenError
+	require.ErrorAs(t, err, &openErr)
+	assert.Equal(t, srv.URL, openErr.URL)
+	assert.EqualError(t, err, "circuit open for "+srv.URL)
+
+	// A failed trial opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, CircuitHalfOpen, breaker.State(srv.URL))
+
+	client.Retry = RetryPolicy{}
+	_, err = client.GetHealth()
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+
+	_, err = client.GetHealth()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+
+	// A successful trial closes it.
+	atomic.StoreInt32(&code, http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = client.GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, breaker.State(srv.URL))
+
+	assert.Equal(t, []stateChange{
+		{srv.URL, CircuitClosed, CircuitOpen},
+		{srv.URL, CircuitOpen, CircuitHalfOpen},
+		{srv.URL, CircuitHalfOpen, CircuitOpen},
+		{srv.URL, CircuitOpen, CircuitHalfOpen},
+		{srv.URL, CircuitHalfOpen, CircuitClosed},
+	}, changes)
+}
+
+func TestClient_CircuitBreakerClientErrors(t *testing.T) {
+	code := int32(http.StatusNotFound)
+	srv, calls := breakerServer(&code)
+	defer srv.Close()
+
+	breaker := NewCircuitBreaker(2, time.Minute)
+	client := New(srv.URL, WithCircuitBreaker(breaker))
+
+	for i := 0; i < 5; i++ {
+		_, err := client.GetHealth()
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+
+	assert.Equal(t, int32(5), atomic.LoadInt32(calls))
+	assert.Equal(t, CircuitClosed, breaker.State(srv.URL))
+}
+
+func TestClient_CircuitBreakerPerURL(t *testing.T) {
+	failing := int32(http.StatusServiceUnavailable)
+	bad, _ := breakerServer(&failing)
+	defer bad.Close()
+
+	ok := int32(http.StatusOK)
+	good, calls := breakerServer(&ok)
+	defer good.Close()
+
+	breaker := NewCircuitBreaker(1, time.Minute)
+
+	_, err := New(bad.URL, WithCircuitBreaker(breaker)).GetHealth()
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+	assert.Equal(t, CircuitOpen, breaker.State(bad.URL))
+
+	_, err = New(good.URL, WithCircuitBreaker(breaker)).GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, breaker.State(good.URL))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestClient_CircuitBreakerHalfOpenTrial(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	failing := int32(1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		if atomic.LoadInt32(&failing) == 1 {
+			res.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		close(started)
+		<-release
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	breaker := NewCircuitBreaker(1, 10*time.Millisecond)
+	client := New(srv.URL, WithCircuitBreaker(breaker))
+
+	_, err := client.GetHealth()
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+
+	atomic.StoreInt32(&failing, 0)
+	time.Sleep(20 * time.Millisecond)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GetHealth()
+		done <- err
+	}()
+	<-started
+
+	// Only one trial request at a time.
+	_, err = client.GetHealth()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	var openErr *CircuitOpenError
+	require.ErrorAs(t, err, &openErr)
+	assert.True(t, openErr.Until.After(time.Now()))
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, CircuitClosed, breaker.State(srv.URL))
+}
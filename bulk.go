@@ -0,0 +1,104 @@
+package eos_contract_api_client
+
+import (
+	"strings"
+	"sync"
+)
+
+// MaxBulkIDs is the maximum number of ids GetAssetsByIDs sends in one request.
+const MaxBulkIDs = 1000
+
+// MaxBulkQueryLength is the maximum length in bytes of the ids
+// query parameter GetAssetsByIDs sends in one request.
+const MaxBulkQueryLength = 4096
+
+// BulkWorkers is the number of requests GetAssetsByIDs runs at the same time.
+const BulkWorkers = 4
+
+// chunkIDs splits ids into chunks that fit in one request,
+// duplicate ids are removed.
+func chunkIDs(ids []string) [][]string {
+	seen := make(map[string]bool, len(ids))
+	chunks := [][]string{}
+	chunk, size := []string{}, 0
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// Ids are separated by an escaped comma (%2C).
+		n := len(id)
+		if len(chunk) > 0 {
+			n += 3
+		}
+
+		if len(chunk) > 0 && (len(chunk) >= MaxBulkIDs || size+n > MaxBulkQueryLength) {
+			chunks = append(chunks, chunk)
+			chunk, size, n = []string{}, 0, len(id)
+		}
+
+		chunk = append(chunk, id)
+		size += n
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+//	GetAssetsByIDs - Fetches "/atomicassets/v1/assets" from API for a list of asset ids
+//
+// The ids are fetched in chunks, concurrently. Returns the assets found keyed
+// by asset id and the ids that were not found, in the order they were given.
+// ---------------------------------------------------------
+func (c *Client) GetAssetsByIDs(ids []string, opts ...RequestOption) (map[string]Asset, []string, error) {
+	chunks := chunkIDs(ids)
+	results := make([]AssetsResponse, len(chunks))
+	errs := make([]error, len(chunks))
+
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < BulkWorkers && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				params := AssetsRequestParams{IDs: strings.Join(chunks[i], ","), Limit: len(chunks[i])}
+				results[i], errs[i] = c.GetAssets(params, opts...)
+			}
+		}()
+	}
+
+	for i := range chunks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	assets := make(map[string]Asset, len(ids))
+	for _, res := range results {
+		for _, a := range res.Data {
+			assets[a.ID] = a
+		}
+	}
+
+	missing := []string{}
+	for _, chunk := range chunks {
+		for _, id := range chunk {
+			if _, ok := assets[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+	}
+	return assets, missing, nil
+}
@@ -0,0 +1,115 @@
+package eos_contract_api_client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkIDs(t *testing.T) {
+	assert.Empty(t, chunkIDs(nil))
+	assert.Equal(t, [][]string{{"1", "2", "3"}}, chunkIDs([]string{"1", "2", "1", "3", "2"}))
+
+	ids := []string{}
+	for i := 0; i < 5000; i++ {
+		ids = append(ids, strconv.Itoa(1099667509880+i))
+	}
+
+	chunks := chunkIDs(ids)
+	assert.Greater(t, len(chunks), 1)
+
+	got := []string{}
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), MaxBulkIDs)
+		assert.LessOrEqual(t, len(strings.Join(chunk, "%2C")), MaxBulkQueryLength)
+		got = append(got, chunk...)
+	}
+	assert.Equal(t, ids, got)
+}
+
+func TestClient_GetAssetsByIDs(t *testing.T) {
+	mu := sync.Mutex{}
+	requests := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/atomicassets/v1/assets", req.URL.Path)
+
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		ids := strings.Split(req.URL.Query().Get("ids"), ",")
+		assert.Equal(t, strconv.Itoa(len(ids)), req.URL.Query().Get("limit"))
+
+		// Odd ids do not exist.
+		data := []map[string]string{}
+		for _, id := range ids {
+			if n, _ := strconv.Atoi(id); n%2 == 0 {
+				data = append(data, map[string]string{"asset_id": id, "name": "asset " + id})
+			}
+		}
+
+		b, err := json.Marshal(map[string]interface{}{"success": true, "data": data})
+		require.NoError(t, err)
+
+		res.Header().Add("Content-type", "application/json")
+		res.Write(b)
+	}))
+	defer srv.Close()
+
+	ids := []string{}
+	expected := []string{}
+	for i := 0; i < 2100; i++ {
+		ids = append(ids, strconv.Itoa(i))
+		if i%2 == 1 {
+			expected = append(expected, strconv.Itoa(i))
+		}
+	}
+
+	client := New(srv.URL)
+
+	assets, missing, err := client.GetAssetsByIDs(ids)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(chunkIDs(ids)), requests)
+	assert.Len(t, assets, 1050)
+	assert.Equal(t, "asset 1000", assets["1000"].Name)
+	assert.Equal(t, expected, missing)
+}
+
+func TestClient_GetAssetsByIDsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		if strings.Contains(req.URL.Query().Get("ids"), ",1500,") {
+			res.WriteHeader(http.StatusTooManyRequests)
+			res.Write([]byte(`{"success":false,"message":"Rate limit"}`))
+			return
+		}
+		res.Write([]byte(`{"success":true,"data":[]}`))
+	}))
+	defer srv.Close()
+
+	ids := []string{}
+	for i := 0; i < 2000; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+
+	client := New(srv.URL)
+
+	assets, missing, err := client.GetAssetsByIDs(ids)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Nil(t, assets)
+	assert.Nil(t, missing)
+
+	assets, missing, err = client.GetAssetsByIDs(nil)
+	require.NoError(t, err)
+	assert.Empty(t, assets)
+	assert.Empty(t, missing)
+}
@@ -0,0 +1,181 @@
+package eos_contract_api_client
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is the backend used by Client to store responses.
+//
+// Implementations must be safe for concurrent use. Expired entries
+// may be kept by the backend, the client checks CacheEntry.Expires itself.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+type CacheEntry struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Expires    time.Time   `json:"expires"`
+}
+
+func (e *CacheEntry) Expired(now time.Time) bool {
+	return !now.Before(e.Expires)
+}
+
+// revalidate sets the conditional request headers for the entry's
+// validators on h. It returns false if the entry has no validators.
+func (e *CacheEntry) revalidate(h http.Header) bool {
+	etag := e.Header.Get("ETag")
+	modified := e.Header.Get("Last-Modified")
+
+	if len(etag) > 0 {
+		h.Set("If-None-Match", etag)
+	}
+	if len(modified) > 0 {
+		h.Set("If-Modified-Since", modified)
+	}
+	return len(etag) > 0 || len(modified) > 0
+}
+
+func isConditional(h http.Header) bool {
+	return len(h.Get("If-None-Match")) > 0 || len(h.Get("If-Modified-Since")) > 0
+}
+
+// response returns a copy of the entry, so changes to the response
+// never reach the cache.
+func (e *CacheEntry) response() *Response {
+	r := &Response{StatusCode: e.StatusCode, Header: e.Header, Body: e.Body}
+	return r.clone()
+}
+
+// CacheTTL maps an endpoint path prefix to the duration responses
+// from that endpoint are cached. The longest matching prefix is used
+// and paths without a match are not cached.
+//
+// Responses with an ETag or Last-Modified header are kept after they
+// expire and revalidated with the server, a zero duration means that
+// every request is revalidated.
+type CacheTTL map[string]time.Duration
+
+func (t CacheTTL) match(path string) (string, time.Duration, bool) {
+	prefix, ttl, found := "", time.Duration(0), false
+	for p, d := range t {
+		if strings.HasPrefix(path, p) && (!found || len(p) > len(prefix)) {
+			prefix, ttl, found = p, d, true
+		}
+	}
+	return prefix, ttl, found
+}
+
+// CacheResult is the outcome of a cache lookup.
+type CacheResult int
+
+const (
+	CacheMiss CacheResult = iota
+	CacheHit
+	CacheRevalidated
+)
+
+func (r CacheResult) String() string {
+	switch r {
+	case CacheHit:
+		return "hit"
+	case CacheRevalidated:
+		return "revalidated"
+	}
+	return "miss"
+}
+
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+
+	// Revalidated is the number of stale responses the server
+	// confirmed as not modified.
+	Revalidated uint64
+}
+
+func cacheKey(method string, path string, query string) string {
+	key := method + " " + path
+	if len(query) > 0 {
+		key += "?" + query
+	}
+	return key
+}
+
+// MemoryCache
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used
+// entry once it holds more than size entries. A size of 0 means unbounded.
+type MemoryCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		return el.Value.(*memoryCacheItem).entry, true
+	}
+	return nil, false
+}
+
+func (c *MemoryCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*memoryCacheItem).entry = entry
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	for c.size > 0 && c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*memoryCacheItem).key)
+	}
+}
+
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,283 @@
+package eos_contract_api_client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTTL_Match(t *testing.T) {
+	ttl := CacheTTL{
+		"/atomicassets/v1/assets":               time.Minute,
+		"/atomicassets/v1/assets/1099667509880": time.Hour,
+		"/health":                               time.Second,
+	}
+
+	tests := []struct {
+		path   string
+		prefix string
+		want   time.Duration
+		found  bool
+	}{
+		{"/atomicassets/v1/assets", "/atomicassets/v1/assets", time.Minute, true},
+		{"/atomicassets/v1/assets/1234", "/atomicassets/v1/assets", time.Minute, true},
+		{"/atomicassets/v1/assets/1099667509880", "/atomicassets/v1/assets/1099667509880", time.Hour, true},
+		{"/health", "/health", time.Second, true},
+		{"/atomicmarket/v1/sales", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			prefix, d, found := ttl.match(tt.path)
+			assert.Equal(t, tt.prefix, prefix)
+			assert.Equal(t, tt.want, d)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestMemoryCache_Evict(t *testing.T) {
+	c := NewMemoryCache(2)
+
+	c.Set("a", &CacheEntry{Body: []byte("a")})
+	c.Set("b", &CacheEntry{Body: []byte("b")})
+
+	// Touch "a" so "b" becomes the least recently used.
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	c.Set("c", &CacheEntry{Body: []byte("c")})
+
+	assert.Equal(t, 2, c.Len())
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	e, ok := c.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), e.Body)
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+}
+
+func TestFileCache(t *testing.T) {
+	c, err := NewFileCache(t.TempDir())
+	require.NoError(t, err)
+
+	_, ok := c.Get("GET /health")
+	assert.False(t, ok)
+
+	expected := &CacheEntry{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       []byte(`{"success":true}`),
+		Expires:    time.Date(2022, time.November, 21, 15, 11, 19, 0, time.UTC),
+	}
+
+	c.Set("GET /health", expected)
+
+	e, ok := c.Get("GET /health")
+	require.True(t, ok)
+	assert.Equal(t, expected, e)
+
+	c.Delete("GET /health")
+	_, ok = c.Get("GET /health")
+	assert.False(t, ok)
+}
+
+func TestClient_Cache(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":[],"query_time":1669043479123}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Cache = NewMemoryCache(10)
+	client.CacheTTL = CacheTTL{"/atomicassets/v1/assets": time.Minute}
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetAssets(AssetsRequestParams{Owner: "farmersworld"})
+		require.NoError(t, err)
+	}
+
+	// Different query is a different key.
+	_, err := client.GetAssets(AssetsRequestParams{Owner: "someone"})
+	require.NoError(t, err)
+
+	// Not matched by CacheTTL.
+	_, err = client.GetAssetSales("1099667509880", AssetSalesRequestParams{})
+	require.NoError(t, err)
+	_, err = client.GetAssetSales("1099667509880", AssetSalesRequestParams{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, map[string]CacheStats{
+		"/atomicassets/v1/assets": {Hits: 2, Misses: 2},
+	}, client.CacheStats())
+}
+
+func TestClient_CacheMutatingMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":[],"query_time":1669043479123}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithKeepResponse())
+	client.Cache = NewMemoryCache(10)
+	client.CacheTTL = CacheTTL{"/atomicassets/v1/assets": time.Minute}
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			resp, err := next(r)
+			if resp != nil {
+				resp.Header.Add("X-Seen", "1")
+			}
+			return resp, err
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.GetAssets(AssetsRequestParams{})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"1"}, res.Header.Values("X-Seen"))
+		}()
+	}
+	wg.Wait()
+
+	// Changes made by middleware never reach the cached entry.
+	e, ok := client.Cache.Get(cacheKey("GET", "/atomicassets/v1/assets", ""))
+	require.True(t, ok)
+	assert.Empty(t, e.Header.Get("X-Seen"))
+}
+
+func TestClient_CacheExpired(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	cache := NewMemoryCache(10)
+	client := New(srv.URL)
+	client.Cache = cache
+	client.CacheTTL = CacheTTL{"/health": time.Minute}
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+
+	e, ok := cache.Get(cacheKey("GET", "/health", ""))
+	require.True(t, ok)
+	e.Expires = time.Now().Add(-time.Second)
+
+	_, err = client.GetHealth()
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_CacheSkipsErrors(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.Header().Add("Content-type", "application/json")
+		res.WriteHeader(404)
+		res.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Cache = NewMemoryCache(10)
+	client.CacheTTL = CacheTTL{"/": time.Minute}
+
+	client.GetHealth()
+	client.GetHealth()
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_CacheRevalidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		value     string
+		condition string
+	}{
+		{"ETag", "ETag", `W/"1b-4d8b"`, "If-None-Match"},
+		{"Last-Modified", "Last-Modified", "Mon, 21 Nov 2022 15:11:19 GMT", "If-Modified-Since"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, notModified := 0, 0
+			srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				calls++
+				if req.Header.Get(tt.condition) == tt.value {
+					notModified++
+					res.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				res.Header().Add("Content-type", "application/json")
+				res.Header().Add(tt.header, tt.value)
+				res.Write([]byte(`{"success":true,"data":[{"asset_id":"1099667509880"}],"query_time":1669043479123}`))
+			}))
+			defer srv.Close()
+
+			client := New(srv.URL)
+			client.Cache = NewMemoryCache(10)
+			client.CacheTTL = CacheTTL{"/atomicassets/v1/assets": 0}
+
+			for i := 0; i < 3; i++ {
+				a, err := client.GetAssets(AssetsRequestParams{Owner: "farmersworld"})
+				require.NoError(t, err)
+				assert.Equal(t, 200, a.HTTPStatusCode)
+				require.Len(t, a.Data, 1)
+				assert.Equal(t, "1099667509880", a.Data[0].ID)
+			}
+
+			assert.Equal(t, 3, calls)
+			assert.Equal(t, 2, notModified)
+			assert.Equal(t, map[string]CacheStats{
+				"/atomicassets/v1/assets": {Misses: 1, Revalidated: 2},
+			}, client.CacheStats())
+		})
+	}
+}
+
+func TestClient_CacheNoValidators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Empty(t, req.Header.Get("If-None-Match"))
+		assert.Empty(t, req.Header.Get("If-Modified-Since"))
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	cache := NewMemoryCache(10)
+	client := New(srv.URL)
+	client.Cache = cache
+	client.CacheTTL = CacheTTL{"/health": 0}
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+	_, err = client.GetHealth()
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, cache.Len())
+}
@@ -0,0 +1,210 @@
+package eos_contract_api_client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// CassetteMode is the mode of a Cassette.
+type CassetteMode int
+
+const (
+	// CassetteReplay serves recorded responses and fails on other requests.
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests and appends them to the cassette.
+	CassetteRecord
+)
+
+// CassetteEntry is a recorded request and response, one json object
+// per line in the cassette file.
+type CassetteEntry struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Cassette is an http.RoundTripper that records requests to a json
+// lines file, or replays them from it. Use it with WithTransport.
+//
+// Request headers in DefaultRedactHeaders are redacted in the file.
+type Cassette struct {
+	Mode CassetteMode
+
+	// Transport sends the requests in record mode,
+	// http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+
+	mu      sync.Mutex
+	file    *os.File
+	entries []CassetteEntry
+	used    []bool
+}
+
+// OpenCassette opens the cassette at path. In record mode the file
+// is created if it does not exist, in replay mode it must exist.
+func OpenCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{Mode: mode}
+
+	if mode == CassetteRecord {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		c.file = f
+		return c, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 64*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(bytes.TrimSpace(s.Bytes())) < 1 {
+			continue
+		}
+
+		e := CassetteEntry{}
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		c.entries = append(c.entries, e)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	c.used = make([]bool, len(c.entries))
+	return c, nil
+}
+
+// Close closes the cassette file.
+func (c *Cassette) Close() error {
+	if c.file != nil {
+		return c.file.Close()
+	}
+	return nil
+}
+
+// Entries returns the entries loaded in replay mode.
+func (c *Cassette) Entries() []CassetteEntry {
+	return append([]CassetteEntry(nil), c.entries...)
+}
+
+// canonicalURL sorts the query parameters of u.
+func canonicalURL(u *url.URL) string {
+	v := *u
+	v.RawQuery = u.Query().Encode()
+	return v.String()
+}
+
+func (c *Cassette) RoundTrip(r *http.Request) (*http.Response, error) {
+	if c.Mode == CassetteRecord {
+		return c.record(r)
+	}
+	return c.replay(r)
+}
+
+func (c *Cassette) record(r *http.Request) (*http.Response, error) {
+	t := c.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+
+	// Let the transport handle compression so bodies are stored as text.
+	out := r.Clone(r.Context())
+	out.Header.Del("Accept-Encoding")
+
+	resp, err := t.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	header.Del("Content-Length")
+
+	b, err := json.Marshal(CassetteEntry{
+		Method:        r.Method,
+		URL:           canonicalURL(r.URL),
+		RequestHeader: redactHeaders(out.Header, nil),
+		StatusCode:    resp.StatusCode,
+		Header:        header,
+		Body:          string(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.file.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay serves the first unused entry matching r, the last matching
+// entry is served again when all have been used.
+func (c *Cassette) replay(r *http.Request) (*http.Response, error) {
+	uri := canonicalURL(r.URL)
+
+	c.mu.Lock()
+	match := -1
+	for i, e := range c.entries {
+		if e.Method == r.Method && e.URL == uri {
+			match = i
+			if !c.used[i] {
+				break
+			}
+		}
+	}
+	if match >= 0 {
+		c.used[match] = true
+	}
+	c.mu.Unlock()
+
+	if match < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, r.Method, uri)
+	}
+
+	e := c.entries[match]
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(e.Body))),
+		ContentLength: int64(len(e.Body)),
+		Request:       r,
+	}, nil
+}
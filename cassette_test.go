@@ -0,0 +1,121 @@
+package eos_contract_api_client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette_RecordReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		// The client's encodings are not sent when recording.
+		assert.NotEqual(t, "gzip, br", req.Header.Get("Accept-Encoding"))
+		res.Header().Add("Content-type", "application/json")
+		if req.URL.Path == "/health" {
+			fmt.Fprintf(res, `{"success":true,"data":{"chain":{"head_block":%d}}}`, calls)
+			return
+		}
+		res.WriteHeader(http.StatusNotFound)
+		res.Write([]byte(`{"success":false,"message":"Asset not found"}`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+
+	rec, err := OpenCassette(path, CassetteRecord)
+	require.NoError(t, err)
+
+	client := New(srv.URL, WithTransport(rec), WithAuthToken("secret"))
+
+	for i := 1; i <= 2; i++ {
+		h, err := client.GetHealth()
+		require.NoError(t, err)
+		assert.Equal(t, int64(i), h.Data.Chain.HeadBlock)
+	}
+
+	_, err = client.GetAsset("1099667509880")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.GetAssets(AssetsRequestParams{Owner: "farmersworld", Limit: 10})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, rec.Close())
+	srv.Close()
+
+	// The cassette is json lines with redacted credentials.
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	lines := 0
+	for s := bufio.NewScanner(f); s.Scan(); lines++ {
+		e := CassetteEntry{}
+		require.NoError(t, json.Unmarshal(s.Bytes(), &e))
+		assert.Equal(t, "[REDACTED]", e.RequestHeader.Get("Authorization"))
+	}
+	assert.Equal(t, 4, lines)
+
+	// Replay without the server.
+	play, err := OpenCassette(path, CassetteReplay)
+	require.NoError(t, err)
+	require.Len(t, play.Entries(), 4)
+
+	client = New(srv.URL, WithTransport(play))
+
+	// Repeated requests are replayed in order, then the last one is repeated.
+	for _, expected := range []int64{1, 2, 2} {
+		h, err := client.GetHealth()
+		require.NoError(t, err)
+		assert.Equal(t, expected, h.Data.Chain.HeadBlock)
+	}
+
+	_, err = client.GetAsset("1099667509880")
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 404, apiErr.StatusCode)
+	assert.Equal(t, "Asset not found", apiErr.Message.String)
+
+	// Query parameter order does not matter.
+	_, err = client.GetAssets(AssetsRequestParams{Limit: 10, Owner: "farmersworld"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.GetAssets(AssetsRequestParams{Owner: "someone"})
+	assert.ErrorIs(t, err, ErrUnmatchedRequest)
+
+	// Nothing was sent while replaying.
+	assert.Equal(t, 4, calls)
+}
+
+func TestCassette_ReplayErrors(t *testing.T) {
+	_, err := OpenCassette(filepath.Join(t.TempDir(), "missing.jsonl"), CassetteReplay)
+	assert.True(t, os.IsNotExist(err))
+
+	path := filepath.Join(t.TempDir(), "broken.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"method\":\"GET\"}\n\n{broken\n"), 0o644))
+
+	_, err = OpenCassette(path, CassetteReplay)
+	assert.EqualError(t, err, path+":3: invalid character 'b' looking for beginning of object key string")
+}
+
+func TestCassette_RetryUnmatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.jsonl")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	play, err := OpenCassette(path, CassetteReplay)
+	require.NoError(t, err)
+
+	client := New("http://localhost", WithTransport(play), WithRetry(RetryPolicy{MaxRetries: 3}))
+
+	_, err = client.GetHealth()
+	assert.ErrorIs(t, err, ErrUnmatchedRequest)
+	assert.False(t, DefaultRetryable(err))
+}
@@ -1,8 +1,15 @@
 package eos_contract_api_client
 
 import (
-	"fmt"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/imroc/req/v3"
 	"github.com/sonh/qs"
@@ -11,12 +18,156 @@ import (
 type Client struct {
 	Url  string
 	Host string
+
+	// Header is sent with every request.
+	Header http.Header
+
+	// Timeout limits the time of each http call, 0 means no limit.
+	Timeout time.Duration
+
+	// Retry controls how failed requests are retried.
+	Retry RetryPolicy
+
+	// KeepResponse keeps the raw body, headers and latency in the
+	// HTTPResponse of every response.
+	KeepResponse bool
+
+	// DisableCompression stops the client from asking for
+	// gzip or brotli compressed responses.
+	DisableCompression bool
+
+	// Transport is used for the http calls when set.
+	// It must be set before the first request is made.
+	Transport http.RoundTripper
+
+	// Proxy is the http, https or socks5 proxy used for the http calls,
+	// the environment (HTTP_PROXY etc.) is used if nil.
+	Proxy *url.URL
+
+	// TLSConfig is used for https connections, see WithRootCAs
+	// and WithClientCertificate.
+	TLSConfig *tls.Config
+
+	// UnixSocket is the path of a unix domain socket the client connects
+	// to instead of the host in Url.
+	//
+	// Proxy, TLSConfig and UnixSocket are ignored if Transport is set and
+	// must be set before the first request is made.
+	UnixSocket string
+
+	// Cache stores successful GET responses for the durations in CacheTTL.
+	// Caching is disabled when either is nil.
+	Cache    Cache
+	CacheTTL CacheTTL
+
+	// Coalesce makes concurrent identical GET requests share
+	// a single in-flight http call.
+	Coalesce bool
+
+	// Middleware wraps every request, the first one is the outermost.
+	Middleware []Middleware
+
+	// Tracer starts a span for every request.
+	Tracer Tracer
+
+	// Metrics receives measurements for every request, see NewMetrics.
+	Metrics MetricsCollector
+
+	// Logger logs every request at LogLevel or above. Header and query
+	// parameter values in RedactHeaders and RedactParams are redacted,
+	// in addition to DefaultRedactHeaders and DefaultRedactParams.
+	Logger        Logger
+	LogLevel      LogLevel
+	RedactHeaders []string
+	RedactParams  []string
+
+	// Breaker fails requests fast while the circuit for Url is open.
+	Breaker *CircuitBreaker
+
+	// Freshness rejects requests to a node that is behind,
+	// see WithMinBlock and WithMaxLag.
+	Freshness Freshness
+
+	once          sync.Once
+	client        *req.Client
+	mu            sync.Mutex
+	cacheStats    map[string]CacheStats
+	flight        flightGroup
+	coalesceStats CoalesceStats
+	health        healthCache
 }
 
-func New(url string) *Client {
-	return &Client{
+func New(url string, opts ...Option) *Client {
+	c := &Client{
 		Url: url,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) httpClient() *req.Client {
+	c.once.Do(func() {
+		c.client = req.C()
+		if c.Transport != nil {
+			c.client.GetClient().Transport = c.Transport
+			return
+		}
+
+		if c.Proxy != nil {
+			c.client.SetProxy(http.ProxyURL(c.Proxy))
+		}
+
+		if c.TLSConfig != nil {
+			cfg := c.TLSConfig.Clone()
+
+			// Keep http2 enabled.
+			if len(cfg.NextProtos) < 1 {
+				cfg.NextProtos = c.client.GetTLSClientConfig().NextProtos
+			}
+			c.client.SetTLSClientConfig(cfg)
+		}
+
+		if len(c.UnixSocket) > 0 {
+			c.client.SetDial(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				d := net.Dialer{}
+				return d.DialContext(ctx, "unix", c.UnixSocket)
+			})
+		}
+	})
+	return c.client
+}
+
+// routes are the endpoints used by the client, {} marks a path parameter.
+var routes = []string{
+	"/health",
+	"/atomicassets/v1/assets",
+	"/atomicassets/v1/assets/{asset_id}",
+	"/atomicassets/v1/assets/{asset_id}/logs",
+	"/atomicmarket/v1/assets/{asset_id}/sales",
+}
+
+// endpoint returns the route matching path, or path if there is none.
+func endpoint(path string) string {
+	segments := strings.Split(path, "/")
+
+next:
+	for _, route := range routes {
+		rs := strings.Split(route, "/")
+		if len(rs) != len(segments) {
+			continue
+		}
+
+		for i, s := range rs {
+			if s != segments[i] && !strings.HasPrefix(s, "{") {
+				continue next
+			}
+		}
+		return route
+	}
+	return path
 }
 
 func isContentType(t string, expected string) bool {
@@ -27,53 +178,371 @@ func isContentType(t string, expected string) bool {
 	return t == expected
 }
 
-func (c *Client) send(method string, path string, params interface{}) (*req.Response, error) {
-	r := req.C().R()
+// CacheStats returns the cache hits and misses for each CacheTTL prefix.
+func (c *Client) CacheStats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make(map[string]CacheStats, len(c.cacheStats))
+	for k, v := range c.cacheStats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// CoalesceStats returns how many http calls was made and how many
+// requests was deduplicated when Coalesce is enabled.
+func (c *Client) CoalesceStats() CoalesceStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.coalesceStats
+}
+
+func (c *Client) countCache(r *Request, prefix string, result CacheResult) {
+	if c.Metrics != nil {
+		c.Metrics.ObserveCache(r.Endpoint, result)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cacheStats == nil {
+		c.cacheStats = make(map[string]CacheStats)
+	}
+
+	s := c.cacheStats[prefix]
+	switch result {
+	case CacheHit:
+		s.Hits++
+	case CacheRevalidated:
+		s.Revalidated++
+	default:
+		s.Misses++
+	}
+	c.cacheStats[prefix] = s
+}
+
+func (c *Client) newRequest(method string, path string, params interface{}, opts []RequestOption) (*Request, error) {
+	r := &Request{
+		Context:  context.Background(),
+		Method:   method,
+		BaseURL:  c.Url,
+		Path:     path,
+		Endpoint: endpoint(path),
+		Query:    url.Values{},
+		Header:   c.Header.Clone(),
+		MinBlock: c.Freshness.MinBlock,
+		MaxLag:   c.Freshness.MaxLag,
+	}
+
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
 
 	if params != nil {
-		query, err := qs.NewEncoder().Values(params)
+		values, err := qs.NewEncoder().Values(params)
 		if err != nil {
 			return nil, err
 		}
-		r.SetQueryString(query.Encode())
+		r.Query = values
 	}
 
 	if len(c.Host) > 0 {
-		r.SetHeader("Host", c.Host)
+		r.Header.Set("Host", c.Host)
 	}
 
-	resp, err := r.Send(method, c.Url+path)
+	if c.Transport == nil {
+		if c.Proxy != nil {
+			r.proxy = c.Proxy.String()
+		}
+		r.unixSocket = c.UnixSocket
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	c.negotiateEncoding(r.Header)
+	injectTraceParent(r.Context, r.Header)
+	return r, nil
+}
+
+func (c *Client) send(method string, path string, params interface{}, opts ...RequestOption) (*Response, error) {
+	return c.exec(method, path, params, opts, false)
+}
+
+// exec builds a request and passes it through the handler chain.
+// If stream is set the response body is left unread and the caller
+// must close it.
+func (c *Client) exec(method string, path string, params interface{}, opts []RequestOption, stream bool) (resp *Response, err error) {
+	r, err := c.newRequest(method, path, params, opts)
 	if err != nil {
 		return nil, err
 	}
+	r.Stream = stream
 
-	t := resp.GetContentType()
-	if !isContentType(t, "application/json") {
-		return nil, fmt.Errorf("invalid content-type '%s', expected 'application/json'", t)
+	if r.Timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context, r.Timeout)
+		defer func() { resp.release(cancel) }()
+		r.Context = ctx
 	}
 
-	if resp.IsError() {
-		r_err := APIError{}
-		if resp.Unmarshal(&r_err) == nil && r_err.Success.Valid && !r_err.Success.Bool {
-			return nil, fmt.Errorf("API Error: %s", r_err.Message.String)
-		}
+	h := c.fresh(c.do, func(ctx context.Context) (*Response, error) {
+		fopts := append(opts[:len(opts):len(opts)], WithContext(ctx))
+		return c.Freshness.Fallback.exec(method, path, params, fopts, stream)
+	})
+
+	// Log what is actually sent, after middleware has changed the request.
+	if c.Logger != nil {
+		h = c.logged(h)
 	}
 
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		h = c.Middleware[i](h)
+	}
+
+	if c.Tracer != nil {
+		h = c.traced(h)
+	}
+
+	if c.Metrics != nil {
+		h = c.measured(h)
+	}
+
+	start := time.Now()
+	resp, err = h(r)
+	if resp != nil {
+		resp.Latency = time.Since(start)
+	}
 	return resp, err
 }
 
+// keepResponse copies the raw response to res if KeepResponse is enabled.
+func (c *Client) keepResponse(res *HTTPResponse, r *Response) {
+	if c.KeepResponse {
+		res.Header = r.Header
+		res.Raw = r.Body
+		res.Latency = r.Latency
+	}
+}
+
+// do is the innermost handler that performs the request.
+func (c *Client) do(r *Request) (*Response, error) {
+	query := r.Query.Encode()
+
+	// Streamed bodies are read once by the caller, so they can not be shared.
+	if r.Stream {
+		return c.fetchRetry(r, query, r.Header.Clone())
+	}
+
+	if c.Coalesce && r.Method == "GET" {
+		// The call outlives r if its caller gives up, so it gets its own copy.
+		fr := *r
+		resp, err, shared := c.flight.do(r.Context, flightKey(r, query), func(ctx context.Context) (*Response, error) {
+			fr.Context = ctx
+			return c.sendCached(&fr, query)
+		})
+		if !shared && err != r.Context.Err() {
+			r.retries = fr.retries
+		}
+
+		c.mu.Lock()
+		if shared {
+			c.coalesceStats.Deduplicated++
+		} else {
+			c.coalesceStats.Calls++
+		}
+		c.mu.Unlock()
+
+		// Give each caller its own copy so middleware can modify it.
+		if resp != nil {
+			resp = resp.clone()
+
+			// Only the caller that made the http call received it over the network.
+			if shared {
+				resp.WireSize, resp.Encoding = 0, ""
+			}
+		}
+		return resp, err
+	}
+
+	return c.sendCached(r, query)
+}
+
+func (c *Client) sendCached(r *Request, query string) (*Response, error) {
+	method, path := r.Method, r.Path
+	var key, prefix string
+	var ttl time.Duration
+	var entry *CacheEntry
+	var cached bool
+	header := r.Header.Clone()
+
+	if c.Cache != nil && method == "GET" {
+		prefix, ttl, cached = c.CacheTTL.match(path)
+	}
+
+	if cached {
+		key = cacheKey(method, path, query)
+		if e, ok := c.Cache.Get(key); ok && !r.NoCache {
+			if !e.Expired(time.Now()) {
+				c.countCache(r, prefix, CacheHit)
+				return e.response(), nil
+			}
+
+			// Stale entry, ask the server if it is still valid.
+			if e.revalidate(header) {
+				entry = e
+			}
+		}
+	}
+
+	resp, err := c.fetchRetry(r, query, header)
+	if err != nil {
+		if cached {
+			c.countCache(r, prefix, CacheMiss)
+		}
+		return nil, err
+	}
+
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		c.countCache(r, prefix, CacheRevalidated)
+		fresh := *entry
+		fresh.Expires = time.Now().Add(ttl)
+		c.Cache.Set(key, &fresh)
+		return fresh.response(), nil
+	}
+
+	if cached {
+		c.countCache(r, prefix, CacheMiss)
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			// Store a copy, the response is handed to middleware.
+			stored := resp.clone()
+			e := &CacheEntry{
+				StatusCode: stored.StatusCode,
+				Header:     stored.Header,
+				Body:       stored.Body,
+				Expires:    time.Now().Add(ttl),
+			}
+
+			// Entries that are expired at once are only useful if they can be revalidated.
+			if ttl > 0 || e.revalidate(http.Header{}) {
+				c.Cache.Set(key, e)
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// checkResponse returns an error if the response is not a successful json response.
+func checkResponse(method string, uri string, status int, header http.Header, body []byte) error {
+	t := header.Get("Content-Type")
+
+	if status < 200 || status > 299 {
+		r_err := &APIError{
+			StatusCode: status,
+			Method:     method,
+			URL:        uri,
+			Header:     header,
+			Body:       snippet(body),
+		}
+
+		// Use the message from the payload if there is one.
+		if isContentType(t, "application/json") {
+			_ = json.Unmarshal(body, r_err)
+		}
+		return r_err
+	}
+
+	if !isContentType(t, "application/json") {
+		return &ContentTypeError{
+			ContentType: t,
+			StatusCode:  status,
+			Method:      method,
+			URL:         uri,
+			Header:      header,
+			Body:        snippet(body),
+		}
+	}
+	return nil
+}
+
+func (c *Client) fetch(req *Request, query string, header http.Header) (*Response, error) {
+	if req.Stream {
+		return c.fetchStream(req, query, header)
+	}
+
+	ctx, method, path := req.Context, req.Method, req.Path
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	r := c.httpClient().R().SetContext(ctx)
+
+	if len(query) > 0 {
+		r.SetQueryString(query)
+	}
+
+	// SetHeader keeps one value per key, so set every value directly.
+	r.Headers = header.Clone()
+
+	uri := req.BaseURL + path
+	if len(query) > 0 {
+		uri += "?" + query
+	}
+
+	resp, err := r.Send(method, req.BaseURL+path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not modified responses has no body.
+	if resp.StatusCode == http.StatusNotModified && isConditional(header) {
+		return &Response{StatusCode: resp.StatusCode, Header: resp.Header}, nil
+	}
+
+	raw, err := resp.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := resp.Header.Get("Content-Encoding")
+	respHeader, body, decodeErr := decodeBody(resp.Header, raw)
+
+	if err := checkResponse(method, uri, resp.StatusCode, respHeader, body); err != nil {
+		return nil, err
+	}
+
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     respHeader,
+		Body:       body,
+		WireSize:   len(raw),
+		Encoding:   encoding,
+	}, nil
+}
+
 //	GetHealth - Fetches "/health" from API
 //
 // ---------------------------------------------------------
-func (c *Client) GetHealth() (Health, error) {
+func (c *Client) GetHealth(opts ...RequestOption) (Health, error) {
 	var health Health
 
-	r, err := c.send("GET", "/health", nil)
+	r, err := c.send("GET", "/health", nil, opts...)
 	if err == nil {
 
 		// Set HTTPStatusCode
 		health.HTTPStatusCode = r.StatusCode
 
+		// Keep raw response
+		c.keepResponse(&health.HTTPResponse, r)
+
 		// Parse json
 		err = r.Unmarshal(&health)
 	}
@@ -83,15 +552,18 @@ func (c *Client) GetHealth() (Health, error) {
 //	GetAsset - Fetches "/atomicassets/v1/assets/{asset_id}" from API
 //
 // ---------------------------------------------------------
-func (c *Client) GetAsset(asset_id string) (AssetResponse, error) {
+func (c *Client) GetAsset(asset_id string, opts ...RequestOption) (AssetResponse, error) {
 	var asset AssetResponse
 
-	r, err := c.send("GET", "/atomicassets/v1/assets/"+asset_id, nil)
+	r, err := c.send("GET", "/atomicassets/v1/assets/"+asset_id, nil, opts...)
 	if err == nil {
 
 		// Set HTTPStatusCode
 		asset.HTTPStatusCode = r.StatusCode
 
+		// Keep raw response
+		c.keepResponse(&asset.HTTPResponse, r)
+
 		// Parse json
 		err = r.Unmarshal(&asset)
 	}
@@ -101,15 +573,18 @@ func (c *Client) GetAsset(asset_id string) (AssetResponse, error) {
 //	GetAssets - Fetches "/atomicassets/v1/assets" from API
 //
 // ---------------------------------------------------------
-func (c *Client) GetAssets(params AssetsRequestParams) (AssetsResponse, error) {
+func (c *Client) GetAssets(params AssetsRequestParams, opts ...RequestOption) (AssetsResponse, error) {
 	var assets AssetsResponse
 
-	r, err := c.send("GET", "/atomicassets/v1/assets", params)
+	r, err := c.send("GET", "/atomicassets/v1/assets", params, opts...)
 	if err == nil {
 
 		// Set HTTPStatusCode
 		assets.HTTPStatusCode = r.StatusCode
 
+		// Keep raw response
+		c.keepResponse(&assets.HTTPResponse, r)
+
 		// Parse json
 		err = r.Unmarshal(&assets)
 	}
@@ -119,15 +594,18 @@ func (c *Client) GetAssets(params AssetsRequestParams) (AssetsResponse, error) {
 //	GetAssetLog - Fetches "/atomicassets/v1/assets/{asset_id}/logs" from API
 //
 // ---------------------------------------------------------
-func (c *Client) GetAssetLog(asset_id string, params LogRequestParams) (AssetLogResponse, error) {
+func (c *Client) GetAssetLog(asset_id string, params LogRequestParams, opts ...RequestOption) (AssetLogResponse, error) {
 	var logs AssetLogResponse
 
-	r, err := c.send("GET", "/atomicassets/v1/assets/"+asset_id+"/logs", params)
+	r, err := c.send("GET", "/atomicassets/v1/assets/"+asset_id+"/logs", params, opts...)
 	if err == nil {
 
 		// Set HTTPStatusCode
 		logs.HTTPStatusCode = r.StatusCode
 
+		// Keep raw response
+		c.keepResponse(&logs.HTTPResponse, r)
+
 		// Parse json
 		err = r.Unmarshal(&logs)
 	}
@@ -137,15 +615,18 @@ func (c *Client) GetAssetLog(asset_id string, params LogRequestParams) (AssetLog
 //	GetAssetSales - Fetches "/atomicmarket/v1/assets/{asset_id}/sales" from API
 //
 // ---------------------------------------------------------
-func (c *Client) GetAssetSales(asset_id string, params AssetSalesRequestParams) (SalesResponse, error) {
+func (c *Client) GetAssetSales(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) (SalesResponse, error) {
 	var sales SalesResponse
 
-	r, err := c.send("GET", "/atomicmarket/v1/assets/"+asset_id+"/sales", params)
+	r, err := c.send("GET", "/atomicmarket/v1/assets/"+asset_id+"/sales", params, opts...)
 	if err == nil {
 
 		// Set HTTPStatusCode
 		sales.HTTPStatusCode = r.StatusCode
 
+		// Keep raw response
+		c.keepResponse(&sales.HTTPResponse, r)
+
 		// Parse json
 		err = r.Unmarshal(&sales)
 	}
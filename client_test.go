@@ -259,10 +259,10 @@ func TestClient_APIErrorEmptyPayload(t *testing.T) {
 
 	client := New(srv.URL)
 
-	health, err := client.GetHealth()
+	_, err := client.GetHealth()
 
-	assert.NoError(t, err)
-	assert.Equal(t, 404, health.HTTPStatusCode)
+	assert.EqualError(t, err, "API Error: 404 Not Found")
+	assert.ErrorIs(t, err, ErrNotFound)
 }
 
 func TestClient_ErrorNoPayload(t *testing.T) {
@@ -792,3 +792,31 @@ func TestGetAssetSaleFilterBuyer(t *testing.T) {
 
 	assert.Equal(t, expected, res.Data)
 }
+
+func TestClient_KeepResponse(t *testing.T) {
+	payload := `{"success":true,"data":{"asset_id":"1099667509880","new_field":"value"},"query_time":1669043479123}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Header().Add("X-Request-Id", "abc")
+		res.Write([]byte(payload))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	a, err := client.GetAsset("1099667509880")
+	require.NoError(t, err)
+	assert.Nil(t, a.Header)
+	assert.Nil(t, a.Raw)
+	assert.Zero(t, a.Latency)
+
+	client = New(srv.URL, WithKeepResponse())
+
+	a, err = client.GetAsset("1099667509880")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", a.Header.Get("X-Request-Id"))
+	assert.JSONEq(t, payload, string(a.Raw))
+	assert.Greater(t, a.Latency, time.Duration(0))
+	assert.Equal(t, "1099667509880", a.Data.ID)
+}
@@ -0,0 +1,121 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type CoalesceStats struct {
+	// Calls is the number of requests that made a http call.
+	Calls uint64
+
+	// Deduplicated is the number of requests that instead waited
+	// for the result of an identical in-flight call.
+	Deduplicated uint64
+}
+
+type flightCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	resp    *Response
+	err     error
+	waiters int
+}
+
+// flightGroup deduplicates concurrent calls with the same key.
+//
+// Callers share the buffered response and decode it themselves,
+// so the decoded values are never aliased between callers.
+//
+// The call runs on a context detached from the caller that started it,
+// so one caller giving up does not fail the others. It is canceled
+// once every caller waiting for it has given up.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) (*Response, error)) (*Response, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	call, shared := g.calls[key]
+	if !shared {
+		fctx, cancel := context.WithCancel(detachedContext{ctx})
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+
+		go func() {
+			call.resp, call.err = fn(fctx)
+
+			g.mu.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.resp, call.err, shared
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Nobody is left to receive the result.
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			call.cancel()
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err(), shared
+	}
+}
+
+// detachedContext keeps the values of its parent but not its deadline
+// or cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+// flightKey returns the key identical requests are coalesced on.
+// Trace headers differ per request but do not change the response,
+// so they are left out.
+func flightKey(r *Request, query string) string {
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		switch http.CanonicalHeaderKey(name) {
+		case "Traceparent", "Tracestate":
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(cacheKey(r.Method, r.Path, query))
+	for _, name := range names {
+		b.WriteString("\n")
+		b.WriteString(http.CanonicalHeaderKey(name))
+		b.WriteString(": ")
+		b.WriteString(strings.Join(r.Header[name], ", "))
+	}
+	return b.String()
+}
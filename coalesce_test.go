@@ -0,0 +1,229 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waiting returns the number of callers waiting on in-flight calls.
+func (g *flightGroup) waiting() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	n := 0
+	for _, call := range g.calls {
+		n += call.waiters
+	}
+	return n
+}
+
+func TestClient_Coalesce(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":{"asset_id":"1099667509880"},"query_time":1669043479123}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Coalesce = true
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]AssetResponse, n)
+	errs := make([]error, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = client.GetAsset("1099667509880")
+		}(i)
+	}
+
+	// Wait until every goroutine is either in flight or waiting for it.
+	for {
+		if atomic.LoadInt32(&calls) == 1 && client.flight.waiting() == n {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "1099667509880", results[i].Data.ID)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, CoalesceStats{Calls: 1, Deduplicated: n - 1}, client.CoalesceStats())
+}
+
+func TestClient_CoalesceDisabled(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+	_, err = client.GetHealth()
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), calls)
+	assert.Equal(t, CoalesceStats{}, client.CoalesceStats())
+}
+
+func TestClient_CoalesceWaiterContext(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":{"asset_id":"1099667509880"},"query_time":1669043479123}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Coalesce = true
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	var leaderErr error
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, leaderErr = client.GetAsset("1099667509880", WithContext(leaderCtx))
+	}()
+
+	var res AssetResponse
+	var err error
+	waiterDone := make(chan struct{})
+	go func() {
+		defer close(waiterDone)
+		res, err = client.GetAsset("1099667509880")
+	}()
+
+	for atomic.LoadInt32(&calls) != 1 || client.flight.waiting() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A waiter with a short deadline gives up without waiting for the call.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, timeoutErr := client.GetAsset("1099667509880", WithContext(ctx))
+	assert.ErrorIs(t, timeoutErr, context.DeadlineExceeded)
+
+	// Canceling the caller that started the call does not fail the others.
+	cancelLeader()
+	<-leaderDone
+	assert.ErrorIs(t, leaderErr, context.Canceled)
+
+	close(release)
+	<-waiterDone
+	require.NoError(t, err)
+	assert.Equal(t, "1099667509880", res.Data.ID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_CoalesceHeaders(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":{"asset_id":"` + req.Header.Get("X-Tenant") + `"},"query_time":1669043479123}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Coalesce = true
+
+	var wg sync.WaitGroup
+	results := make([]AssetResponse, 2)
+	errs := make([]error, 2)
+	for i, tenant := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(i int, tenant string) {
+			defer wg.Done()
+			results[i], errs[i] = client.GetAsset("1099667509880", WithRequestHeader("X-Tenant", tenant))
+		}(i, tenant)
+	}
+
+	for atomic.LoadInt32(&calls) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	assert.Equal(t, "a", results[0].Data.ID)
+	assert.Equal(t, "b", results[1].Data.ID)
+	assert.Equal(t, CoalesceStats{Calls: 2}, client.CoalesceStats())
+}
+
+func TestClient_CoalesceMutatingMiddleware(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		<-release
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Coalesce = true
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			resp, err := next(r)
+			if resp != nil {
+				resp.Header.Set("X-Seen", "1")
+				resp.Body[0] = '{'
+			}
+			return resp, err
+		}
+	})
+
+	const n = 8
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.GetHealth()
+		}(i)
+	}
+
+	for client.flight.waiting() != n {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, CoalesceStats{Calls: 1, Deduplicated: n - 1}, client.CoalesceStats())
+}
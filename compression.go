@@ -0,0 +1,62 @@
+package eos_contract_api_client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// acceptEncoding is sent unless Client.DisableCompression is set
+// or the request already has an Accept-Encoding header.
+const acceptEncoding = "gzip, br"
+
+func (c *Client) negotiateEncoding(h http.Header) {
+	if !c.DisableCompression && len(h.Get("Accept-Encoding")) < 1 {
+		h.Set("Accept-Encoding", acceptEncoding)
+	}
+}
+
+// decompress returns a reader that decodes body as encoding.
+func decompress(encoding string, body io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return ioutil.NopCloser(body), nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "br":
+		return ioutil.NopCloser(brotli.NewReader(body)), nil
+	}
+	return nil, fmt.Errorf("unsupported content-encoding '%s'", encoding)
+}
+
+// decodeBody decompresses body according to the Content-Encoding in header.
+// The returned header has Content-Encoding and Content-Length removed
+// if the body was decompressed.
+func decodeBody(header http.Header, body []byte) (http.Header, []byte, error) {
+	encoding := header.Get("Content-Encoding")
+	if len(encoding) < 1 {
+		return header, body, nil
+	}
+
+	r, err := decompress(encoding, bytes.NewReader(body))
+	if err != nil {
+		return header, body, err
+	}
+	defer r.Close()
+
+	decoded, err := ioutil.ReadAll(r)
+	if err != nil {
+		return header, body, err
+	}
+
+	header = header.Clone()
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	return header, decoded, nil
+}
@@ -0,0 +1,197 @@
+package eos_contract_api_client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compress(t testing.TB, encoding string, data []byte) []byte {
+	buf := bytes.Buffer{}
+	switch encoding {
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		w.Write(data)
+		require.NoError(t, w.Close())
+	case "br":
+		w := brotli.NewWriter(&buf)
+		w.Write(data)
+		require.NoError(t, w.Close())
+	default:
+		buf.Write(data)
+	}
+	return buf.Bytes()
+}
+
+// compressedServer serves payload with the first encoding in Accept-Encoding.
+func compressedServer(t testing.TB, payload []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		encoding := strings.TrimSpace(strings.Split(req.Header.Get("Accept-Encoding"), ",")[0])
+		if encoding == "identity" {
+			encoding = ""
+		}
+
+		res.Header().Add("Content-type", "application/json")
+		if len(encoding) > 0 {
+			res.Header().Add("Content-Encoding", encoding)
+		}
+		res.Write(compress(t, encoding, payload))
+	}))
+}
+
+func TestClient_Compression(t *testing.T) {
+	payload := assetsPayload(20)
+
+	tests := []struct {
+		name     string
+		accept   string
+		encoding string
+	}{
+		{"gzip", "gzip", "gzip"},
+		{"brotli", "br", "br"},
+		{"identity", "identity", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := compressedServer(t, payload)
+			defer srv.Close()
+
+			metrics := NewMetrics()
+			client := New(srv.URL, WithKeepResponse())
+			client.Metrics = metrics
+
+			a, err := client.GetAssets(AssetsRequestParams{}, WithRequestHeader("Accept-Encoding", tt.accept))
+			require.NoError(t, err)
+			require.Len(t, a.Data, 20)
+			assert.Equal(t, asset1.Template, a.Data[0].Template)
+			assert.Empty(t, a.Header.Get("Content-Encoding"))
+
+			wire := len(compress(t, tt.encoding, payload))
+			if len(tt.encoding) > 0 {
+				assert.Less(t, wire, len(payload))
+			}
+
+			buf := bytes.Buffer{}
+			metrics.WriteTo(&buf)
+			assert.Contains(t, buf.String(), `eos_contract_api_client_response_wire_bytes_total{method="GET",endpoint="/atomicassets/v1/assets"} `+strconv.Itoa(wire)+"\n")
+			assert.Contains(t, buf.String(), `eos_contract_api_client_response_bytes_total{method="GET",endpoint="/atomicassets/v1/assets"} `+strconv.Itoa(len(payload))+"\n")
+		})
+	}
+}
+
+func TestClient_CompressionCoalesce(t *testing.T) {
+	payload := assetsPayload(20)
+	release := make(chan struct{})
+	compressed := compressedServer(t, payload)
+	defer compressed.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		<-release
+		compressed.Config.Handler.ServeHTTP(res, req)
+	}))
+	defer srv.Close()
+
+	metrics := NewMetrics()
+	client := New(srv.URL)
+	client.Coalesce = true
+	client.Metrics = metrics
+
+	const n = 8
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetAssets(AssetsRequestParams{})
+			assert.NoError(t, err)
+		}()
+	}
+
+	for client.flight.waiting() != n {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	// Responses shared with other callers were not received over the network.
+	stats := client.CoalesceStats()
+	require.Equal(t, CoalesceStats{Calls: 1, Deduplicated: n - 1}, stats)
+	wire := len(compress(t, "gzip", payload))
+
+	buf := bytes.Buffer{}
+	metrics.WriteTo(&buf)
+	assert.Contains(t, buf.String(), `eos_contract_api_client_response_wire_bytes_total{method="GET",endpoint="/atomicassets/v1/assets"} `+strconv.Itoa(wire*int(stats.Calls))+"\n")
+	assert.Contains(t, buf.String(), `eos_contract_api_client_response_encoding_total{endpoint="/atomicassets/v1/assets",encoding="gzip"} `+strconv.Itoa(int(stats.Calls))+"\n")
+	assert.Contains(t, buf.String(), `eos_contract_api_client_response_bytes_total{method="GET",endpoint="/atomicassets/v1/assets"} `+strconv.Itoa(n*len(payload))+"\n")
+}
+
+func TestClient_AcceptEncoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "gzip, br", req.Header.Get("Accept-Encoding"))
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+}
+
+func TestClient_CompressedError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Header().Add("Content-Encoding", "gzip")
+		res.WriteHeader(500)
+		res.Write(compress(t, "gzip", []byte(`{"success":false,"message":"Some internal error"}`)))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetHealth()
+	assert.EqualError(t, err, "API Error: Some internal error")
+}
+
+func TestClient_UnsupportedEncoding(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Header().Add("Content-Encoding", "zstd")
+		res.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetHealth()
+	assert.EqualError(t, err, "unsupported content-encoding 'zstd'")
+}
+
+func TestClient_StreamCompressed(t *testing.T) {
+	srv := compressedServer(t, assetsPayload(5))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	for _, encoding := range []string{"br", "gzip"} {
+		n := 0
+		_, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error {
+			n++
+			return nil
+		}, WithRequestHeader("Accept-Encoding", encoding))
+
+		require.NoError(t, err, encoding)
+		assert.Equal(t, 5, n, encoding)
+	}
+}
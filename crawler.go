@@ -0,0 +1,130 @@
+package eos_contract_api_client
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AssetCrawler walks every asset matching a query in asset_id order,
+// using lower_bound instead of page offsets. New assets do not shift the
+// results, and a crawl can be resumed from its Checkpoint.
+type AssetCrawler struct {
+	client *Client
+	params AssetsRequestParams
+	opts   []RequestOption
+
+	assets     []Asset
+	index      int
+	checkpoint string
+	last       bool
+	err        error
+}
+
+// CrawlAssets returns a crawler over the assets matching params, resuming
+// after checkpoint if it is not empty. params.Sort, Order and Page are
+// ignored, LowerBound and UpperBound limit the range of asset ids.
+func (c *Client) CrawlAssets(params AssetsRequestParams, checkpoint string, opts ...RequestOption) *AssetCrawler {
+	params.Sort = "asset_id"
+	params.Order = string(SortAscending)
+	params.Page = 0
+
+	if params.Limit < 1 {
+		params.Limit = DefaultPageLimit
+	}
+
+	return &AssetCrawler{
+		client:     c,
+		params:     params,
+		opts:       opts,
+		index:      -1,
+		checkpoint: checkpoint,
+	}
+}
+
+// nextID returns the id after id.
+func nextID(id string) (string, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset id '%s': %w", id, err)
+	}
+	return strconv.FormatUint(n+1, 10), nil
+}
+
+// after reports if asset id a sorts after b.
+func after(a string, b string) bool {
+	return len(a) > len(b) || (len(a) == len(b) && a > b)
+}
+
+func (cr *AssetCrawler) fetch() error {
+	params := cr.params
+
+	if len(cr.checkpoint) > 0 {
+		lower, err := nextID(cr.checkpoint)
+		if err != nil {
+			return err
+		}
+
+		if len(params.LowerBound) < 1 || after(lower, params.LowerBound) {
+			params.LowerBound = lower
+		}
+	}
+
+	res, err := cr.client.GetAssets(params, cr.opts...)
+	if err != nil {
+		return err
+	}
+
+	cr.last = len(res.Data) < params.Limit
+
+	// Skip anything at or before the checkpoint in case the server
+	// treats the bound differently.
+	cr.assets = cr.assets[:0]
+	for _, a := range res.Data {
+		if len(cr.checkpoint) < 1 || after(a.ID, cr.checkpoint) {
+			cr.assets = append(cr.assets, a)
+		}
+	}
+	cr.index = -1
+	return nil
+}
+
+// Next advances to the next asset, it returns false when there are
+// no more assets or an error occurred.
+func (cr *AssetCrawler) Next() bool {
+	if cr.err != nil {
+		return false
+	}
+
+	for cr.index+1 >= len(cr.assets) {
+		if cr.last {
+			return false
+		}
+
+		if cr.err = cr.fetch(); cr.err != nil {
+			return false
+		}
+
+		// A page with nothing new means the crawl is done.
+		if len(cr.assets) < 1 {
+			return false
+		}
+	}
+
+	cr.index++
+	cr.checkpoint = cr.assets[cr.index].ID
+	return true
+}
+
+func (cr *AssetCrawler) Asset() Asset {
+	return cr.assets[cr.index]
+}
+
+func (cr *AssetCrawler) Err() error {
+	return cr.err
+}
+
+// Checkpoint returns a token for the position of the crawler.
+// Passing it to CrawlAssets resumes after the current asset.
+func (cr *AssetCrawler) Checkpoint() string {
+	return cr.checkpoint
+}
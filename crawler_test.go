@@ -0,0 +1,132 @@
+package eos_contract_api_client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keysetServer serves assets with the given ids, filtered by an inclusive
+// lower_bound and upper_bound and sorted by asset_id.
+func keysetServer(t *testing.T, ids *[]uint64) (*httptest.Server, *[]string) {
+	queries := []string{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		queries = append(queries, req.URL.RawQuery)
+
+		assert.Equal(t, "asset_id", q.Get("sort"))
+		assert.Equal(t, "asc", q.Get("order"))
+		assert.Empty(t, q.Get("page"))
+
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		lower, _ := strconv.ParseUint(q.Get("lower_bound"), 10, 64)
+		upper, err := strconv.ParseUint(q.Get("upper_bound"), 10, 64)
+		if err != nil {
+			upper = ^uint64(0)
+		}
+
+		sorted := append([]uint64(nil), *ids...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		data := []map[string]string{}
+		for _, id := range sorted {
+			if id >= lower && id <= upper && len(data) < limit {
+				data = append(data, map[string]string{"asset_id": strconv.FormatUint(id, 10)})
+			}
+		}
+
+		b, err := json.Marshal(map[string]interface{}{"success": true, "data": data})
+		require.NoError(t, err)
+
+		res.Header().Add("Content-type", "application/json")
+		res.Write(b)
+	}))
+
+	return srv, &queries
+}
+
+func TestClient_CrawlAssets(t *testing.T) {
+	ids := []uint64{5, 1099667509880, 7, 9, 99, 100, 1000}
+	srv, queries := keysetServer(t, &ids)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	got := []string{}
+	cr := client.CrawlAssets(AssetsRequestParams{CollectionName: "farmersworld", Limit: 3, Sort: "minted", Page: 4}, "")
+	for cr.Next() {
+		got = append(got, cr.Asset().ID)
+
+		// Assets added during the crawl must not shift the results,
+		// ids before the checkpoint are not visited.
+		if len(got) == 2 {
+			ids = append(ids, 1, 50)
+		}
+	}
+
+	require.NoError(t, cr.Err())
+	assert.Equal(t, []string{"5", "7", "9", "50", "99", "100", "1000", "1099667509880"}, got)
+	assert.Equal(t, "1099667509880", cr.Checkpoint())
+	assert.Equal(t, []string{
+		"collection_name=farmersworld&limit=3&order=asc&sort=asset_id",
+		"collection_name=farmersworld&limit=3&lower_bound=10&order=asc&sort=asset_id",
+		"collection_name=farmersworld&limit=3&lower_bound=101&order=asc&sort=asset_id",
+	}, *queries)
+}
+
+func TestClient_CrawlAssetsResume(t *testing.T) {
+	ids := []uint64{1, 2, 3, 4, 5, 6}
+	srv, _ := keysetServer(t, &ids)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	first := []string{}
+	cr := client.CrawlAssets(AssetsRequestParams{Limit: 2}, "")
+	for len(first) < 3 && cr.Next() {
+		first = append(first, cr.Asset().ID)
+	}
+	require.NoError(t, cr.Err())
+
+	rest := []string{}
+	cr = client.CrawlAssets(AssetsRequestParams{Limit: 2}, cr.Checkpoint())
+	for cr.Next() {
+		rest = append(rest, cr.Asset().ID)
+	}
+	require.NoError(t, cr.Err())
+
+	assert.Equal(t, []string{"1", "2", "3"}, first)
+	assert.Equal(t, []string{"4", "5", "6"}, rest)
+}
+
+func TestClient_CrawlAssetsBounds(t *testing.T) {
+	ids := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+	srv, _ := keysetServer(t, &ids)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	got := []string{}
+	cr := client.CrawlAssets(AssetsRequestParams{Limit: 2, LowerBound: "3", UpperBound: "6"}, "")
+	for cr.Next() {
+		got = append(got, cr.Asset().ID)
+	}
+
+	require.NoError(t, cr.Err())
+	assert.Equal(t, []string{"3", "4", "5", "6"}, got)
+}
+
+func TestClient_CrawlAssetsInvalidCheckpoint(t *testing.T) {
+	client := New("http://localhost")
+
+	cr := client.CrawlAssets(AssetsRequestParams{}, "abc")
+	assert.False(t, cr.Next())
+	assert.EqualError(t, cr.Err(), `invalid asset id 'abc': strconv.ParseUint: parsing "abc": invalid syntax`)
+}
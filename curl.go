@@ -0,0 +1,68 @@
+package eos_contract_api_client
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// shellQuote quotes s for a posix shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Curl returns a curl command that sends the request. The command
+// includes all headers, also credentials such as Authorization, and
+// the proxy or unix socket the client connects through.
+// Compressed responses are decoded by curl with --compressed.
+func (r *Request) Curl() string {
+	b := strings.Builder{}
+	b.WriteString("curl")
+
+	if r.Method != "GET" {
+		b.WriteString(" -X " + r.Method)
+	}
+
+	if len(r.unixSocket) > 0 {
+		b.WriteString(" --unix-socket " + shellQuote(r.unixSocket))
+	}
+
+	if len(r.proxy) > 0 {
+		b.WriteString(" -x " + shellQuote(r.proxy))
+	}
+
+	if len(r.Header.Get("Accept-Encoding")) > 0 {
+		b.WriteString(" --compressed")
+	}
+
+	keys := make([]string, 0, len(r.Header))
+	for k := range r.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range r.Header[k] {
+			b.WriteString(" -H " + shellQuote(k+": "+v))
+		}
+	}
+
+	b.WriteString(" " + shellQuote(r.URL()))
+	return b.String()
+}
+
+// CurlDump returns a Middleware that writes every request to w as a
+// curl command. Add it last to include changes made by other middleware.
+func CurlDump(w io.Writer) Middleware {
+	mu := sync.Mutex{}
+	return func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			mu.Lock()
+			fmt.Fprintln(w, r.Curl())
+			mu.Unlock()
+			return next(r)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package eos_contract_api_client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequest_Curl(t *testing.T) {
+	r := &Request{
+		Method:  "GET",
+		BaseURL: "https://wax.api.atomicassets.io",
+		Path:    "/atomicassets/v1/assets",
+		Query:   url.Values{"owner": {"farmersworld"}, "match": {"it's"}},
+		Header:  http.Header{"X-B": {"2"}, "X-A": {"1"}},
+	}
+
+	assert.Equal(t, `curl -H 'X-A: 1' -H 'X-B: 2' 'https://wax.api.atomicassets.io/atomicassets/v1/assets?match=it%27s&owner=farmersworld'`, r.Curl())
+
+	r.Method = "POST"
+	r.Query = url.Values{}
+	r.Header = http.Header{"X-Quote": {"it's"}}
+	assert.Equal(t, `curl -X POST -H 'X-Quote: it'\''s' 'https://wax.api.atomicassets.io/atomicassets/v1/assets'`, r.Curl())
+}
+
+func TestClient_CurlConnection(t *testing.T) {
+	proxy, _ := url.Parse("http://proxy.local:3128")
+	client := New("http://localhost", WithHost("api.example.com"), WithProxy(proxy))
+
+	r, err := client.newRequest("GET", "/health", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `curl -x 'http://proxy.local:3128' --compressed -H 'Accept-Encoding: gzip, br' -H 'Host: api.example.com' 'http://localhost/health'`, r.Curl())
+
+	client = New("http://localhost", WithUnixSocket("/run/api.sock"))
+	client.DisableCompression = true
+	r, err = client.newRequest("GET", "/health", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `curl --unix-socket '/run/api.sock' 'http://localhost/health'`, r.Curl())
+
+	// The transport decides how to connect if it is set.
+	client = New("http://localhost", WithUnixSocket("/run/api.sock"), WithTransport(http.DefaultTransport))
+	r, err = client.newRequest("GET", "/health", nil, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, r.Curl(), "--unix-socket")
+}
+
+func TestClient_CurlDump(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	out := bytes.Buffer{}
+	client := New(srv.URL, WithAuthToken("secret"))
+	client.DisableCompression = true
+	client.Use(CurlDump(&out))
+
+	_, err := client.GetAssets(AssetsRequestParams{Owner: "farmersworld", Limit: 10})
+	require.NoError(t, err)
+
+	_, err = client.GetHealth(WithRequestHeader("X-Request-Id", "1"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "curl -H 'Authorization: Bearer secret' '"+srv.URL+"/atomicassets/v1/assets?limit=10&owner=farmersworld'\n"+
+		"curl -H 'Authorization: Bearer secret' -H 'X-Request-Id: 1' '"+srv.URL+"/health'\n", out.String())
+}
@@ -0,0 +1,101 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrServerUnavailable = errors.New("server unavailable")
+	ErrBadContentType    = errors.New("bad content-type")
+	ErrStale             = errors.New("stale")
+	ErrCircuitOpen       = errors.New("circuit open")
+	ErrUnmatchedRequest  = errors.New("unmatched request")
+)
+
+// MaxErrorBodySize is the maximum number of bytes of the response body
+// kept in APIError and ContentTypeError.
+const MaxErrorBodySize = 1024
+
+func snippet(body []byte) []byte {
+	if len(body) > MaxErrorBodySize {
+		body = body[:MaxErrorBodySize]
+	}
+	return append([]byte(nil), body...)
+}
+
+func isServerUnavailable(code int) bool {
+	return code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable ||
+		code == http.StatusGatewayTimeout
+}
+
+// ContentTypeError is returned when the API responds with
+// something other than json.
+type ContentTypeError struct {
+	ContentType string
+	StatusCode  int
+	Method      string
+	URL         string
+	Header      http.Header
+	Body        []byte
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("invalid content-type '%s', expected 'application/json'", e.ContentType)
+}
+
+func (e *ContentTypeError) Is(target error) bool {
+	return target == ErrBadContentType
+}
+
+// statusCode returns the http status code of a request's outcome,
+// or 0 if no response was received.
+func statusCode(resp *Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+
+	var ctErr *ContentTypeError
+	if errors.As(err, &ctErr) {
+		return ctErr.StatusCode
+	}
+	return 0
+}
+
+// errorKind classifies err for metrics and logging.
+func errorKind(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrNotFound):
+		return "not_found"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrServerUnavailable):
+		return "server_unavailable"
+	case errors.Is(err, ErrBadContentType):
+		return "bad_content_type"
+	case errors.Is(err, ErrStale):
+		return "stale"
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return "api"
+	}
+	return "transport"
+}
@@ -0,0 +1,178 @@
+package eos_contract_api_client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	sentinels := []error{ErrNotFound, ErrRateLimited, ErrServerUnavailable, ErrBadContentType}
+
+	tests := []struct {
+		code int
+		want error
+	}{
+		{400, nil},
+		{404, ErrNotFound},
+		{429, ErrRateLimited},
+		{500, nil},
+		{502, ErrServerUnavailable},
+		{503, ErrServerUnavailable},
+		{504, ErrServerUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.code), func(t *testing.T) {
+			err := &APIError{StatusCode: tt.code}
+			for _, s := range sentinels {
+				assert.Equal(t, s == tt.want, errors.Is(err, s), s.Error())
+			}
+		})
+	}
+}
+
+func TestClient_APIErrorTyped(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.WriteHeader(404)
+		res.Write([]byte(`{"success":false,"message":"Asset not found"}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetAssetLog("1099667509880", LogRequestParams{Limit: 10})
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 404, apiErr.StatusCode)
+	assert.Equal(t, "Asset not found", apiErr.Message.String)
+	assert.Equal(t, "GET", apiErr.Method)
+	assert.Equal(t, srv.URL+"/atomicassets/v1/assets/1099667509880/logs?limit=10", apiErr.URL)
+}
+
+func TestClient_ContentTypeErrorTyped(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "text/html")
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetHealth()
+
+	assert.True(t, errors.Is(err, ErrBadContentType))
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	var ctErr *ContentTypeError
+	require.True(t, errors.As(err, &ctErr))
+	assert.Equal(t, "text/html", ctErr.ContentType)
+	assert.Equal(t, 200, ctErr.StatusCode)
+	assert.Equal(t, srv.URL+"/health", ctErr.URL)
+}
+
+func TestClient_ErrorResponses(t *testing.T) {
+	large := make([]byte, MaxErrorBodySize+100)
+	for i := range large {
+		large[i] = 'x'
+	}
+
+	tests := []struct {
+		name        string
+		code        int
+		contentType string
+		body        string
+		err         string
+		message     string
+		sentinel    error
+	}{
+		{
+			name:        "html from proxy",
+			code:        502,
+			contentType: "text/html",
+			body:        "<html><body>502 Bad Gateway</body></html>",
+			err:         "API Error: 502 Bad Gateway",
+			sentinel:    ErrServerUnavailable,
+		},
+		{
+			name:        "json envelope",
+			code:        500,
+			contentType: "application/json",
+			body:        `{"success":false,"message":"Some internal error"}`,
+			err:         "API Error: Some internal error",
+			message:     "Some internal error",
+		},
+		{
+			name:        "json without envelope",
+			code:        404,
+			contentType: "application/json",
+			body:        `{}`,
+			err:         "API Error: 404 Not Found",
+			sentinel:    ErrNotFound,
+		},
+		{
+			name:        "invalid json",
+			code:        503,
+			contentType: "application/json; charset=utf-8",
+			body:        `{"success":fal`,
+			err:         "API Error: 503 Service Unavailable",
+			sentinel:    ErrServerUnavailable,
+		},
+		{
+			name:        "empty body",
+			code:        429,
+			contentType: "text/plain",
+			body:        "",
+			err:         "API Error: 429 Too Many Requests",
+			sentinel:    ErrRateLimited,
+		},
+		{
+			name:        "truncated body",
+			code:        500,
+			contentType: "text/plain",
+			body:        string(large),
+			err:         "API Error: 500 Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Header().Add("Content-type", tt.contentType)
+				res.Header().Add("X-Request-Id", "abc")
+				res.WriteHeader(tt.code)
+				res.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := New(srv.URL)
+
+			_, err := client.GetHealth()
+			require.EqualError(t, err, tt.err)
+
+			if tt.sentinel != nil {
+				assert.ErrorIs(t, err, tt.sentinel)
+			}
+			assert.False(t, errors.Is(err, ErrBadContentType))
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.code, apiErr.StatusCode)
+			assert.Equal(t, tt.message, apiErr.Message.String)
+			assert.Equal(t, "abc", apiErr.Header.Get("X-Request-Id"))
+
+			expected := tt.body
+			if len(expected) > MaxErrorBodySize {
+				expected = expected[:MaxErrorBodySize]
+			}
+			assert.Equal(t, expected, string(apiErr.Body))
+		})
+	}
+}
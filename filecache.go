@@ -0,0 +1,66 @@
+package eos_contract_api_client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FileCache is a Cache that stores each entry as a json file in Dir.
+type FileCache struct {
+	Dir string
+}
+
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileCache{Dir: dir}, nil
+}
+
+func (c *FileCache) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *FileCache) Get(key string) (*CacheEntry, bool) {
+	b, err := ioutil.ReadFile(c.filename(key))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := CacheEntry{}
+	if json.Unmarshal(b, &entry) != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (c *FileCache) Set(key string, entry *CacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so readers never see a partial entry.
+	tmp, err := ioutil.TempFile(c.Dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil || os.Rename(tmp.Name(), c.filename(key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (c *FileCache) Delete(key string) {
+	os.Remove(c.filename(key))
+}
@@ -0,0 +1,179 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Freshness makes the client check that the node has indexed recent
+// enough blocks before a request is sent. Requests to a stale node
+// fail with a StaleError, unless the node catches up within Wait or
+// Fallback is set.
+type Freshness struct {
+	// MinBlock is the lowest head block accepted, see WithMinBlock.
+	MinBlock int64
+
+	// MaxLag is the maximum time the head block may be behind, see WithMaxLag.
+	MaxLag time.Duration
+
+	// Wait is how long to wait for a stale node to catch up,
+	// 0 fails right away.
+	Wait time.Duration
+
+	// HealthInterval is how long the head block from "/health" is cached,
+	// it is also how often the node is checked while waiting.
+	// Defaults to one second.
+	HealthInterval time.Duration
+
+	// Fallback is sent the request when the node is stale.
+	// A fallback is tried at most once per request, so clients
+	// may be each other's fallback.
+	Fallback *Client
+}
+
+func (f Freshness) interval() time.Duration {
+	if f.HealthInterval > 0 {
+		return f.HealthInterval
+	}
+	return time.Second
+}
+
+// WithMinBlock rejects the request if the node has not indexed block n.
+func WithMinBlock(n int64) RequestOption {
+	return func(r *Request) {
+		r.MinBlock = n
+	}
+}
+
+// WithMaxLag rejects the request if the node's head block is older than d.
+func WithMaxLag(d time.Duration) RequestOption {
+	return func(r *Request) {
+		r.MaxLag = d
+	}
+}
+
+// StaleError is returned when the node is behind the
+// MinBlock or MaxLag of the request.
+type StaleError struct {
+	HeadBlock int64
+	HeadTime  time.Time
+	MinBlock  int64
+	MaxLag    time.Duration
+}
+
+func (e *StaleError) Error() string {
+	if e.HeadBlock < e.MinBlock {
+		return fmt.Sprintf("stale node: head block %d is before block %d", e.HeadBlock, e.MinBlock)
+	}
+	return fmt.Sprintf("stale node: head block %d is more than %s behind", e.HeadBlock, e.MaxLag)
+}
+
+func (e *StaleError) Is(target error) bool {
+	return target == ErrStale
+}
+
+type healthCache struct {
+	mu    sync.Mutex
+	chain ChainHealth
+	at    time.Time
+}
+
+func (c *Client) chainHealth(ctx context.Context) (ChainHealth, error) {
+	c.health.mu.Lock()
+	chain, at := c.health.chain, c.health.at
+	c.health.mu.Unlock()
+
+	if time.Since(at) < c.Freshness.interval() {
+		return chain, nil
+	}
+
+	h, err := c.GetHealth(WithContext(ctx), WithNoCache())
+	if err != nil {
+		return chain, err
+	}
+
+	c.health.mu.Lock()
+	c.health.chain, c.health.at = h.Data.Chain, time.Now()
+	c.health.mu.Unlock()
+	return h.Data.Chain, nil
+}
+
+func stale(chain ChainHealth, r *Request) error {
+	head := chain.HeadTime.Time()
+	if chain.HeadBlock < r.MinBlock || (r.MaxLag > 0 && time.Since(head) > r.MaxLag) {
+		return &StaleError{
+			HeadBlock: chain.HeadBlock,
+			HeadTime:  head,
+			MinBlock:  r.MinBlock,
+			MaxLag:    r.MaxLag,
+		}
+	}
+	return nil
+}
+
+// checkFreshness returns an error if the node is stale for r after waiting
+// up to Freshness.Wait for it to catch up.
+func (c *Client) checkFreshness(r *Request) error {
+	if (r.MinBlock < 1 && r.MaxLag < 1) || r.Endpoint == "/health" {
+		return nil
+	}
+
+	deadline := time.Now().Add(c.Freshness.Wait)
+	for {
+		chain, err := c.chainHealth(r.Context)
+		if err != nil {
+			return err
+		}
+
+		err = stale(chain, r)
+		if err == nil || !time.Now().Before(deadline) {
+			return err
+		}
+
+		select {
+		case <-time.After(c.Freshness.interval()):
+		case <-r.Context.Done():
+			return r.Context.Err()
+		}
+	}
+}
+
+// fallbackChain is the clients a request has been sent to because
+// of stale nodes, stored in the request context.
+type fallbackChain struct {
+	client *Client
+	prev   *fallbackChain
+}
+
+type fallbackKey struct{}
+
+func (f *fallbackChain) contains(c *Client) bool {
+	for ; f != nil; f = f.prev {
+		if f.client == c {
+			return true
+		}
+	}
+	return false
+}
+
+// fresh returns a handler that checks freshness before calling next.
+// Stale requests are passed to fallback with a context that records c,
+// unless Fallback has already been tried for the request.
+func (c *Client) fresh(next Handler, fallback func(context.Context) (*Response, error)) Handler {
+	return func(r *Request) (*Response, error) {
+		err := c.checkFreshness(r)
+		if err == nil {
+			return next(r)
+		}
+
+		chain, _ := r.Context.Value(fallbackKey{}).(*fallbackChain)
+		fb := c.Freshness.Fallback
+		if !errors.Is(err, ErrStale) || fb == nil || fb == c || chain.contains(fb) {
+			return nil, err
+		}
+		return fallback(context.WithValue(r.Context, fallbackKey{}, &fallbackChain{c, chain}))
+	}
+}
@@ -0,0 +1,191 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthServer reports head as the head block, increasing it by step on
+// every health check, with a head time lag behind now.
+func healthServer(head *int64, step int64, lag time.Duration) (*httptest.Server, *int32, *int32) {
+	checks, requests := new(int32), new(int32)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+
+		if req.URL.Path == "/health" {
+			atomic.AddInt32(checks, 1)
+			block := atomic.AddInt64(head, step) - step
+			ts := time.Now().Add(-lag).UnixNano() / 1e6
+			fmt.Fprintf(res, `{"success":true,"data":{"chain":{"status":"OK","head_block":%d,"head_time":"%d"}}}`, block, ts)
+			return
+		}
+
+		atomic.AddInt32(requests, 1)
+		res.Write([]byte(`{"success":true,"data":[],"query_time":1669043479123}`))
+	}))
+
+	return srv, checks, requests
+}
+
+func TestClient_FreshnessMinBlock(t *testing.T) {
+	head := int64(100)
+	srv, checks, requests := healthServer(&head, 0, 0)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetAssets(AssetsRequestParams{}, WithMinBlock(100))
+	require.NoError(t, err)
+
+	// The head block is cached.
+	_, err = client.GetAssets(AssetsRequestParams{}, WithMinBlock(50))
+	require.NoError(t, err)
+
+	_, err = client.GetAssets(AssetsRequestParams{}, WithMinBlock(101))
+	assert.ErrorIs(t, err, ErrStale)
+	assert.EqualError(t, err, "stale node: head block 100 is before block 101")
+
+	var staleErr *StaleError
+	require.ErrorAs(t, err, &staleErr)
+	assert.Equal(t, int64(100), staleErr.HeadBlock)
+	assert.Equal(t, int64(101), staleErr.MinBlock)
+
+	// Requests without requirements never check.
+	_, err = client.GetAssets(AssetsRequestParams{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(checks))
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestClient_FreshnessMaxLag(t *testing.T) {
+	head := int64(100)
+	srv, _, requests := healthServer(&head, 0, time.Minute)
+	defer srv.Close()
+
+	client := New(srv.URL, WithFreshness(Freshness{MaxLag: time.Hour}))
+
+	_, err := client.GetAssets(AssetsRequestParams{})
+	require.NoError(t, err)
+
+	_, err = client.GetAssets(AssetsRequestParams{}, WithMaxLag(10*time.Second))
+	assert.ErrorIs(t, err, ErrStale)
+	assert.EqualError(t, err, "stale node: head block 100 is more than 10s behind")
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestClient_FreshnessWait(t *testing.T) {
+	head := int64(100)
+	srv, checks, requests := healthServer(&head, 1, 0)
+	defer srv.Close()
+
+	client := New(srv.URL, WithFreshness(Freshness{
+		Wait:           time.Second,
+		HealthInterval: time.Millisecond,
+	}))
+
+	_, err := client.GetAssets(AssetsRequestParams{}, WithMinBlock(103))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(4), atomic.LoadInt32(checks))
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+
+	// Give up after Wait.
+	client.Freshness.Wait = 10 * time.Millisecond
+	_, err = client.GetAssets(AssetsRequestParams{}, WithMinBlock(1000000))
+	assert.ErrorIs(t, err, ErrStale)
+
+	// Or when the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.Freshness.Wait = time.Minute
+	_, err = client.GetAssets(AssetsRequestParams{}, WithMinBlock(1000000), WithContext(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_FreshnessFallback(t *testing.T) {
+	staleHead, freshHead := int64(100), int64(200)
+	stale, _, staleRequests := healthServer(&staleHead, 0, 0)
+	defer stale.Close()
+	fresh, _, freshRequests := healthServer(&freshHead, 0, 0)
+	defer fresh.Close()
+
+	client := New(stale.URL, WithFreshness(Freshness{Fallback: New(fresh.URL)}))
+
+	_, err := client.GetAssets(AssetsRequestParams{}, WithMinBlock(150))
+	require.NoError(t, err)
+
+	_, err = client.GetAssets(AssetsRequestParams{}, WithMinBlock(250))
+	assert.ErrorIs(t, err, ErrStale)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(staleRequests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(freshRequests))
+}
+
+func TestClient_FreshnessFallbackCycle(t *testing.T) {
+	headA, headB := int64(100), int64(100)
+	a, _, aRequests := healthServer(&headA, 0, 0)
+	defer a.Close()
+	b, _, bRequests := healthServer(&headB, 0, 0)
+	defer b.Close()
+
+	clientA, clientB := New(a.URL), New(b.URL)
+	clientA.Freshness.Fallback = clientB
+	clientB.Freshness.Fallback = clientA
+
+	_, err := clientA.GetAssets(AssetsRequestParams{}, WithMinBlock(150))
+	assert.ErrorIs(t, err, ErrStale)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(aRequests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(bRequests))
+}
+
+type recordedMetrics struct {
+	requests []RequestMetrics
+}
+
+func (m *recordedMetrics) ObserveRequest(r RequestMetrics)                  { m.requests = append(m.requests, r) }
+func (m *recordedMetrics) ObserveCache(endpoint string, result CacheResult) {}
+
+func TestClient_FreshnessMeasured(t *testing.T) {
+	head := int64(100)
+	srv, _, _ := healthServer(&head, 0, 0)
+	defer srv.Close()
+
+	m := &recordedMetrics{}
+	client := New(srv.URL)
+	client.Metrics = m
+
+	_, err := client.GetAssets(AssetsRequestParams{}, WithMinBlock(150))
+	assert.ErrorIs(t, err, ErrStale)
+
+	// The health check is measured on its own.
+	require.Len(t, m.requests, 2)
+	assert.Equal(t, "/health", m.requests[0].Endpoint)
+	assert.Equal(t, "/atomicassets/v1/assets", m.requests[1].Endpoint)
+	assert.Equal(t, "stale", m.requests[1].ErrorKind)
+}
+
+func TestClient_FreshnessHealthError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.WriteHeader(http.StatusServiceUnavailable)
+		res.Write([]byte(`{"success":false,"message":"down"}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.GetAssets(AssetsRequestParams{}, WithMinBlock(1))
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+}
@@ -3,6 +3,7 @@ module github.com/eosswedenorg-go/eos-contract-api-client
 go 1.16
 
 require (
+	github.com/andybalholm/brotli v1.0.4
 	github.com/imroc/req/v3 v3.25.0
 	github.com/sonh/qs v0.6.0
 	github.com/stretchr/testify v1.8.1
@@ -0,0 +1,218 @@
+package eos_contract_api_client
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HAR is an HTTP Archive (version 1.2) as written by HARRecorder.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+
+	// Error is the error of the request, if any.
+	Error string `json:"_error,omitempty"`
+}
+
+type HARPair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARRequest struct {
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	HTTPVersion string    `json:"httpVersion"`
+	Cookies     []HARPair `json:"cookies"`
+	Headers     []HARPair `json:"headers"`
+	QueryString []HARPair `json:"queryString"`
+	HeadersSize int       `json:"headersSize"`
+	BodySize    int       `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Cookies     []HARPair  `json:"cookies"`
+	Headers     []HARPair  `json:"headers"`
+	Content     HARContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// HARRecorder records requests and responses as a HAR. Use Record as
+// a Middleware. Values of DefaultRedactHeaders, DefaultRedactParams,
+// RedactHeaders and RedactParams are redacted.
+type HARRecorder struct {
+	RedactHeaders []string
+	RedactParams  []string
+
+	mu      sync.Mutex
+	entries []HAREntry
+}
+
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{}
+}
+
+func harPairs(m map[string][]string) []HARPair {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := []HARPair{}
+	for _, k := range keys {
+		for _, v := range m[k] {
+			pairs = append(pairs, HARPair{Name: k, Value: v})
+		}
+	}
+	return pairs
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Record is a Middleware that records every request.
+func (h *HARRecorder) Record(next Handler) Handler {
+	return func(r *Request) (*Response, error) {
+		start := time.Now()
+		resp, err := next(r)
+		duration := time.Since(start)
+
+		query := redactValues(r.Query, h.RedactParams)
+		uri := redactURL(r.BaseURL) + r.Path
+		if q := query.Encode(); len(q) > 0 {
+			uri += "?" + q
+		}
+
+		e := HAREntry{
+			StartedDateTime: start.Format("2006-01-02T15:04:05.000Z07:00"),
+			Time:            milliseconds(duration),
+			Request: HARRequest{
+				Method:      r.Method,
+				URL:         uri,
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []HARPair{},
+				Headers:     harPairs(redactHeaders(r.Header, h.RedactHeaders)),
+				QueryString: harPairs(query),
+				HeadersSize: -1,
+			},
+			Response: HARResponse{
+				Status:      statusCode(resp, err),
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []HARPair{},
+				Headers:     []HARPair{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings: HARTimings{Wait: milliseconds(duration)},
+		}
+
+		if e.Response.Status > 0 {
+			e.Response.StatusText = http.StatusText(e.Response.Status)
+		}
+
+		if resp != nil {
+			e.Response.Headers = harPairs(redactHeaders(resp.Header, h.RedactHeaders))
+			e.Response.Content = HARContent{
+				Size:     len(resp.Body),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     string(resp.Body),
+			}
+			if resp.WireSize > 0 {
+				e.Response.BodySize = resp.WireSize
+			}
+		}
+
+		if err != nil {
+			e.Error = redactError(err, h.RedactParams)
+
+			// Error responses are only available from the error.
+			var apiErr *APIError
+			if resp == nil && errors.As(err, &apiErr) {
+				e.Response.Headers = harPairs(redactHeaders(apiErr.Header, h.RedactHeaders))
+				e.Response.Content = HARContent{
+					Size:     len(apiErr.Body),
+					MimeType: apiErr.Header.Get("Content-Type"),
+					Text:     string(apiErr.Body),
+				}
+			}
+		}
+
+		h.mu.Lock()
+		h.entries = append(h.entries, e)
+		h.mu.Unlock()
+		return resp, err
+	}
+}
+
+// HAR returns the recorded requests.
+func (h *HARRecorder) HAR() HAR {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "eos-contract-api-client", Version: "1"},
+		Entries: append([]HAREntry{}, h.entries...),
+	}}
+}
+
+// Reset removes all recorded requests.
+func (h *HARRecorder) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = nil
+}
+
+// WriteTo writes the recorded requests to w as a HAR file.
+func (h *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(h.HAR(), "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(b, '\n'))
+	return int64(n), err
+}
@@ -0,0 +1,84 @@
+package eos_contract_api_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		if req.URL.Path == "/health" {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			res.Write([]byte(`{"success":false,"message":"down"}`))
+			return
+		}
+		res.Write([]byte(`{"success":true,"data":[]}`))
+	}))
+	defer srv.Close()
+
+	har := NewHARRecorder()
+	client := New(srv.URL, WithAuthToken("secret"), WithHost("api.example.com"))
+	client.DisableCompression = true
+	client.Use(har.Record)
+
+	_, err := client.GetAssets(AssetsRequestParams{Owner: "farmersworld", Limit: 10}, WithRequestHeader("X-Request-Id", "1"))
+	require.NoError(t, err)
+
+	_, err = client.GetHealth()
+	require.Error(t, err)
+
+	out := bytes.Buffer{}
+	_, err = har.WriteTo(&out)
+	require.NoError(t, err)
+
+	var h HAR
+	require.NoError(t, json.Unmarshal(out.Bytes(), &h))
+
+	assert.Equal(t, "1.2", h.Log.Version)
+	require.Len(t, h.Log.Entries, 2)
+
+	e := h.Log.Entries[0]
+	assert.Equal(t, "GET", e.Request.Method)
+	assert.Equal(t, srv.URL+"/atomicassets/v1/assets?limit=10&owner=farmersworld", e.Request.URL)
+	assert.Equal(t, []HARPair{{"Authorization", "[REDACTED]"}, {"Host", "api.example.com"}, {"X-Request-Id", "1"}}, e.Request.Headers)
+	assert.Equal(t, []HARPair{{"limit", "10"}, {"owner", "farmersworld"}}, e.Request.QueryString)
+	assert.Equal(t, 200, e.Response.Status)
+	assert.Equal(t, "OK", e.Response.StatusText)
+	assert.Equal(t, HARContent{Size: 26, MimeType: "application/json", Text: `{"success":true,"data":[]}`}, e.Response.Content)
+	assert.Empty(t, e.Error)
+
+	e = h.Log.Entries[1]
+	assert.Equal(t, srv.URL+"/health", e.Request.URL)
+	assert.Equal(t, 503, e.Response.Status)
+	assert.Equal(t, "Service Unavailable", e.Response.StatusText)
+	assert.Equal(t, `{"success":false,"message":"down"}`, e.Response.Content.Text)
+	assert.Equal(t, "API Error: down", e.Error)
+
+	har.Reset()
+	assert.Empty(t, har.HAR().Log.Entries)
+}
+
+func TestHARRecorder_TransportError(t *testing.T) {
+	har := NewHARRecorder()
+	client := New("http://127.0.0.1:0")
+	client.Use(har.Record)
+
+	_, err := client.GetHealth(func(r *Request) {
+		r.Query.Set("api_key", "SECRET")
+	})
+	require.Error(t, err)
+
+	entries := har.HAR().Log.Entries
+	require.Len(t, entries, 1)
+	assert.Equal(t, 0, entries[0].Response.Status)
+	assert.Equal(t, strings.ReplaceAll(err.Error(), "api_key=SECRET", "api_key=%5BREDACTED%5D"), entries[0].Error)
+	assert.NotContains(t, entries[0].Error, "SECRET")
+}
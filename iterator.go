@@ -0,0 +1,151 @@
+package eos_contract_api_client
+
+// DefaultPageLimit is the page size used by iterators when no limit is set.
+const DefaultPageLimit = 100
+
+// pager walks pages lazily, fetch loads a page and returns its length.
+// The last page is the first one shorter than limit.
+type pager struct {
+	page  int
+	limit int
+	index int
+	size  int
+	last  bool
+	err   error
+	fetch func(page int, limit int) (int, error)
+}
+
+func newPager(page int, limit int, fetch func(page int, limit int) (int, error)) pager {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = DefaultPageLimit
+	}
+
+	return pager{page: page, limit: limit, index: -1, fetch: fetch}
+}
+
+func (p *pager) next() bool {
+	if p.err != nil {
+		return false
+	}
+
+	p.index++
+	for p.index >= p.size {
+		if p.last {
+			return false
+		}
+
+		n, err := p.fetch(p.page, p.limit)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		p.page++
+		p.index, p.size = 0, n
+		p.last = n < p.limit
+	}
+	return true
+}
+
+// AssetsIterator iterates over all pages of GetAssets.
+type AssetsIterator struct {
+	pager
+	assets []Asset
+}
+
+// AssetsIter returns an iterator over every asset matching params,
+// starting at params.Page.
+func (c *Client) AssetsIter(params AssetsRequestParams, opts ...RequestOption) *AssetsIterator {
+	it := &AssetsIterator{}
+	it.pager = newPager(params.Page, params.Limit, func(page int, limit int) (int, error) {
+		params.Page, params.Limit = page, limit
+		res, err := c.GetAssets(params, opts...)
+		it.assets = res.Data
+		return len(res.Data), err
+	})
+	return it
+}
+
+// Next advances to the next asset, it returns false when there are
+// no more assets or an error occurred.
+func (it *AssetsIterator) Next() bool {
+	return it.next()
+}
+
+func (it *AssetsIterator) Asset() Asset {
+	return it.assets[it.index]
+}
+
+func (it *AssetsIterator) Err() error {
+	return it.err
+}
+
+// AssetLogIterator iterates over all pages of GetAssetLog.
+type AssetLogIterator struct {
+	pager
+	logs []Log
+}
+
+// AssetLogIter returns an iterator over every log of an asset,
+// starting at params.Page.
+func (c *Client) AssetLogIter(asset_id string, params LogRequestParams, opts ...RequestOption) *AssetLogIterator {
+	it := &AssetLogIterator{}
+	it.pager = newPager(params.Page, params.Limit, func(page int, limit int) (int, error) {
+		params.Page, params.Limit = page, limit
+		res, err := c.GetAssetLog(asset_id, params, opts...)
+		it.logs = res.Data
+		return len(res.Data), err
+	})
+	return it
+}
+
+// Next advances to the next log, it returns false when there are
+// no more logs or an error occurred.
+func (it *AssetLogIterator) Next() bool {
+	return it.next()
+}
+
+func (it *AssetLogIterator) Log() Log {
+	return it.logs[it.index]
+}
+
+func (it *AssetLogIterator) Err() error {
+	return it.err
+}
+
+// AssetSalesIterator iterates over all pages of GetAssetSales.
+type AssetSalesIterator struct {
+	pager
+	sales []AssetSale
+}
+
+// AssetSalesIter returns an iterator over every sale of an asset,
+// starting at params.Page.
+func (c *Client) AssetSalesIter(asset_id string, params AssetSalesRequestParams, opts ...RequestOption) *AssetSalesIterator {
+	it := &AssetSalesIterator{}
+	it.pager = newPager(params.Page, params.Limit, func(page int, limit int) (int, error) {
+		params.Page, params.Limit = page, limit
+		res, err := c.GetAssetSales(asset_id, params, opts...)
+		it.sales = res.Data
+		return len(res.Data), err
+	})
+	return it
+}
+
+// Next advances to the next sale, it returns false when there are
+// no more sales or an error occurred.
+func (it *AssetSalesIterator) Next() bool {
+	return it.next()
+}
+
+func (it *AssetSalesIterator) Sale() AssetSale {
+	return it.sales[it.index]
+}
+
+func (it *AssetSalesIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,145 @@
+package eos_contract_api_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedServer serves total items as pages, item returns the json of the n:th item.
+func pagedServer(t *testing.T, total int, item func(n int) interface{}, failPage int) (*httptest.Server, *[]string) {
+	queries := []string{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.RawQuery)
+
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+
+		res.Header().Add("Content-type", "application/json")
+
+		if page == failPage {
+			res.WriteHeader(500)
+			res.Write([]byte(`{"success":false,"message":"Some internal error"}`))
+			return
+		}
+
+		data := []interface{}{}
+		for n := (page - 1) * limit; n < page*limit && n < total; n++ {
+			data = append(data, item(n))
+		}
+
+		b, err := json.Marshal(map[string]interface{}{"success": true, "data": data})
+		require.NoError(t, err)
+		res.Write(b)
+	}))
+
+	return srv, &queries
+}
+
+func TestClient_AssetsIter(t *testing.T) {
+	srv, queries := pagedServer(t, 7, func(n int) interface{} {
+		return map[string]string{"asset_id": fmt.Sprint(n)}
+	}, 0)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	ids := []string{}
+	it := client.AssetsIter(AssetsRequestParams{Owner: "farmersworld", Limit: 3})
+	for it.Next() {
+		ids = append(ids, it.Asset().ID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6"}, ids)
+	assert.Equal(t, []string{
+		"limit=3&owner=farmersworld&page=1",
+		"limit=3&owner=farmersworld&page=2",
+		"limit=3&owner=farmersworld&page=3",
+	}, *queries)
+
+	// Calling Next after the end does not fetch again.
+	assert.False(t, it.Next())
+	assert.Len(t, *queries, 3)
+}
+
+func TestClient_AssetsIterFullLastPage(t *testing.T) {
+	srv, queries := pagedServer(t, 4, func(n int) interface{} {
+		return map[string]string{"asset_id": fmt.Sprint(n)}
+	}, 0)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	n := 0
+	it := client.AssetsIter(AssetsRequestParams{Limit: 2, Page: 1})
+	for it.Next() {
+		n++
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, 4, n)
+
+	// The empty third page ends the iteration.
+	assert.Equal(t, []string{"limit=2&page=1", "limit=2&page=2", "limit=2&page=3"}, *queries)
+}
+
+func TestClient_AssetsIterDefaultLimit(t *testing.T) {
+	srv, queries := pagedServer(t, 1, func(n int) interface{} {
+		return map[string]string{"asset_id": fmt.Sprint(n)}
+	}, 0)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	it := client.AssetsIter(AssetsRequestParams{Page: 5})
+	assert.False(t, it.Next())
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"limit=100&page=5"}, *queries)
+}
+
+func TestClient_AssetLogIter(t *testing.T) {
+	srv, _ := pagedServer(t, 5, func(n int) interface{} {
+		return map[string]string{"log_id": fmt.Sprint(n)}
+	}, 0)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	ids := []string{}
+	it := client.AssetLogIter("1099667509880", LogRequestParams{Limit: 2, Order: SortAscending})
+	for it.Next() {
+		ids = append(ids, it.Log().ID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, ids)
+}
+
+func TestClient_AssetSalesIterError(t *testing.T) {
+	srv, queries := pagedServer(t, 10, func(n int) interface{} {
+		return map[string]string{"sale_id": fmt.Sprint(n)}
+	}, 2)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	ids := []string{}
+	it := client.AssetSalesIter("1099667509880", AssetSalesRequestParams{Limit: 3})
+	for it.Next() {
+		ids = append(ids, it.Sale().ID)
+	}
+
+	assert.EqualError(t, it.Err(), "API Error: Some internal error")
+	assert.Equal(t, []string{"0", "1", "2"}, ids)
+
+	assert.False(t, it.Next())
+	assert.Len(t, *queries, 2)
+}
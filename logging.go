@@ -0,0 +1,212 @@
+package eos_contract_api_client
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LogLevel is the severity of a log record. The zero value is LogInfo.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota - 1
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	case LogWarn:
+		return "WARN"
+	case LogError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger is used by Client to log requests.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...LogField)
+}
+
+const redacted = "[REDACTED]"
+
+var (
+	DefaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"}
+	DefaultRedactParams  = []string{"api_key", "apikey", "access_token", "token"}
+)
+
+func (c *Client) logged(next Handler) Handler {
+	return func(r *Request) (*Response, error) {
+		start := time.Now()
+		resp, err := next(r)
+		duration := time.Since(start)
+
+		status := statusCode(resp, err)
+
+		// Client errors are usually the callers fault, not the server.
+		level := LogInfo
+		if err != nil {
+			level = LogError
+			if status >= 400 && status < 500 {
+				level = LogWarn
+			}
+		}
+
+		if level < c.LogLevel {
+			return resp, err
+		}
+
+		query := c.redactQuery(r.Query).Encode()
+
+		uri := redactURL(r.BaseURL) + r.Path
+		if len(query) > 0 {
+			uri += "?" + query
+		}
+
+		fields := []LogField{
+			{"method", r.Method},
+			{"url", uri},
+			{"query", query},
+			{"status", status},
+			{"duration", duration},
+		}
+
+		if err != nil {
+			fields = append(fields, LogField{"error", redactError(err, c.RedactParams)})
+		}
+
+		c.Logger.Log(level, "request", fields...)
+
+		// Headers and bodies are only logged at debug level, in a record
+		// of their own so they never reach a backend filtering at info.
+		if c.LogLevel <= LogDebug {
+			debug := []LogField{
+				{"method", r.Method},
+				{"url", uri},
+				{"headers", c.redactHeader(r.Header)},
+			}
+			if resp != nil {
+				debug = append(debug, LogField{"body", string(resp.Body)})
+			}
+			c.Logger.Log(LogDebug, "request body", debug...)
+		}
+		return resp, err
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) redactQuery(q url.Values) url.Values {
+	return redactValues(q, c.RedactParams)
+}
+
+func (c *Client) redactHeader(h http.Header) http.Header {
+	return redactHeaders(h, c.RedactHeaders)
+}
+
+// redactValues redacts DefaultRedactParams and extra from q.
+func redactValues(q url.Values, extra []string) url.Values {
+	out := make(url.Values, len(q))
+	for k, v := range q {
+		if contains(DefaultRedactParams, k) || contains(extra, k) {
+			v = []string{redacted}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactHeaders redacts DefaultRedactHeaders and extra from h.
+func redactHeaders(h http.Header, extra []string) http.Header {
+	out := make(http.Header, len(h))
+	for k, v := range h {
+		if contains(DefaultRedactHeaders, k) || contains(extra, k) {
+			v = []string{redacted}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactURL removes any password from a base url.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
+
+// redactRawURL redacts the password and the query parameters in
+// DefaultRedactParams and extra from a full url.
+func redactRawURL(s string, extra []string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+
+	if len(u.RawQuery) > 0 {
+		u.RawQuery = redactValues(u.Query(), extra).Encode()
+	}
+	if u.User != nil {
+		return u.Redacted()
+	}
+	return u.String()
+}
+
+// redactError returns the message of err. Transport errors include the
+// request url, it is redacted like the logged url.
+func redactError(err error, extra []string) string {
+	msg := err.Error()
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && len(urlErr.URL) > 0 {
+		msg = strings.ReplaceAll(msg, urlErr.URL, redactRawURL(urlErr.URL, extra))
+	}
+	return msg
+}
+
+// StdLogger is a Logger that writes key=value lines to a standard library logger.
+type StdLogger struct {
+	*log.Logger
+}
+
+func NewStdLogger(l *log.Logger) StdLogger {
+	return StdLogger{Logger: l}
+}
+
+func (l StdLogger) Log(level LogLevel, msg string, fields ...LogField) {
+	b := strings.Builder{}
+	b.WriteString(level.String())
+	b.WriteString(" ")
+	b.WriteString(msg)
+
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%q", f.Key, fmt.Sprint(f.Value))
+	}
+
+	l.Logger.Print(b.String())
+}
@@ -0,0 +1,296 @@
+package eos_contract_api_client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsCollector receives measurements from Client.
+type MetricsCollector interface {
+	ObserveRequest(m RequestMetrics)
+	ObserveCache(endpoint string, result CacheResult)
+}
+
+// RequestMetrics describes a finished request.
+type RequestMetrics struct {
+	Method   string
+	Endpoint string
+
+	// StatusCode is 0 if no response was received.
+	StatusCode int
+
+	Duration time.Duration
+
+	// Size is the size of the response body in bytes, WireSize is the
+	// size before decompression and Encoding the Content-Encoding.
+	Size     int
+	WireSize int
+	Encoding string
+
+	Retries int
+
+	// ErrorKind classifies the error, it is empty on success.
+	ErrorKind string
+}
+
+func (c *Client) measured(next Handler) Handler {
+	return func(r *Request) (*Response, error) {
+		start := time.Now()
+		resp, err := next(r)
+
+		m := RequestMetrics{
+			Method:     r.Method,
+			Endpoint:   r.Endpoint,
+			StatusCode: statusCode(resp, err),
+			Duration:   time.Since(start),
+			Retries:    r.retries,
+			ErrorKind:  errorKind(err),
+		}
+
+		if resp != nil {
+			m.Size = len(resp.Body)
+			m.WireSize = resp.WireSize
+			m.Encoding = resp.Encoding
+		}
+
+		c.Metrics.ObserveRequest(m)
+		return resp, err
+	}
+}
+
+// DefaultLatencyBuckets are the latency histogram buckets in seconds used by NewMetrics.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Metrics is a MetricsCollector that keeps per endpoint counters and
+// latency histograms and serves them in the Prometheus text format.
+type Metrics struct {
+	// Namespace is prefixed to every metric name.
+	Namespace string
+
+	mu       sync.Mutex
+	buckets  []float64
+	requests map[[3]string]uint64
+	errors   map[[2]string]uint64
+	latency  map[[2]string]*histogram
+	bytes    map[[2]string]uint64
+	wire     map[[2]string]uint64
+	encoding map[[2]string]uint64
+	retries  map[[2]string]uint64
+	cache    map[[2]string]uint64
+}
+
+func NewMetrics() *Metrics {
+	return NewMetricsWithBuckets(DefaultLatencyBuckets)
+}
+
+func NewMetricsWithBuckets(buckets []float64) *Metrics {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+
+	return &Metrics{
+		Namespace: "eos_contract_api_client",
+		buckets:   b,
+		requests:  make(map[[3]string]uint64),
+		errors:    make(map[[2]string]uint64),
+		latency:   make(map[[2]string]*histogram),
+		bytes:     make(map[[2]string]uint64),
+		wire:      make(map[[2]string]uint64),
+		encoding:  make(map[[2]string]uint64),
+		retries:   make(map[[2]string]uint64),
+		cache:     make(map[[2]string]uint64),
+	}
+}
+
+func (m *Metrics) ObserveRequest(r RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := [2]string{r.Method, r.Endpoint}
+
+	m.requests[[3]string{r.Method, r.Endpoint, strconv.Itoa(r.StatusCode)}]++
+
+	if len(r.ErrorKind) > 0 {
+		m.errors[[2]string{r.Endpoint, r.ErrorKind}]++
+	}
+
+	h, ok := m.latency[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latency[key] = h
+	}
+
+	s := r.Duration.Seconds()
+	for i, le := range m.buckets {
+		if s <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += s
+
+	m.bytes[key] += uint64(r.Size)
+
+	// Only count responses received over the network.
+	if r.WireSize > 0 {
+		encoding := r.Encoding
+		if len(encoding) < 1 {
+			encoding = "identity"
+		}
+		m.wire[key] += uint64(r.WireSize)
+		m.encoding[[2]string{r.Endpoint, encoding}]++
+	}
+	m.retries[key] += uint64(r.Retries)
+}
+
+func (m *Metrics) ObserveCache(endpoint string, result CacheResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cache[[2]string{endpoint, result.String()}]++
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := strings.Builder{}
+	name := func(n string) string {
+		if len(m.Namespace) > 0 {
+			return m.Namespace + "_" + n
+		}
+		return n
+	}
+
+	header := func(n, help, typ string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", n, help, n, typ)
+	}
+
+	n := name("requests_total")
+	header(n, "Number of requests by endpoint and status code.", "counter")
+	for _, k := range sortedKeys3(m.requests) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("method", k[0], "endpoint", k[1], "code", k[2]), m.requests[k])
+	}
+
+	n = name("errors_total")
+	header(n, "Number of failed requests by endpoint and error kind.", "counter")
+	for _, k := range sortedKeys2(m.errors) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("endpoint", k[0], "kind", k[1]), m.errors[k])
+	}
+
+	n = name("request_duration_seconds")
+	header(n, "Request latency by endpoint.", "histogram")
+	for _, k := range sortedHistogramKeys(m.latency) {
+		h := m.latency[k]
+		for i, le := range m.buckets {
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", n, labels("method", k[0], "endpoint", k[1], "le", formatFloat(le)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", n, labels("method", k[0], "endpoint", k[1], "le", "+Inf"), h.count)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", n, labels("method", k[0], "endpoint", k[1]), formatFloat(h.sum))
+		fmt.Fprintf(&b, "%s_count%s %d\n", n, labels("method", k[0], "endpoint", k[1]), h.count)
+	}
+
+	n = name("response_bytes_total")
+	header(n, "Number of response body bytes decoded by endpoint.", "counter")
+	for _, k := range sortedKeys2(m.bytes) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("method", k[0], "endpoint", k[1]), m.bytes[k])
+	}
+
+	n = name("response_wire_bytes_total")
+	header(n, "Number of response body bytes received before decompression by endpoint.", "counter")
+	for _, k := range sortedKeys2(m.wire) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("method", k[0], "endpoint", k[1]), m.wire[k])
+	}
+
+	n = name("response_encoding_total")
+	header(n, "Number of responses received by endpoint and content encoding.", "counter")
+	for _, k := range sortedKeys2(m.encoding) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("endpoint", k[0], "encoding", k[1]), m.encoding[k])
+	}
+
+	n = name("retries_total")
+	header(n, "Number of retried requests by endpoint.", "counter")
+	for _, k := range sortedKeys2(m.retries) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("method", k[0], "endpoint", k[1]), m.retries[k])
+	}
+
+	n = name("cache_total")
+	header(n, "Number of cache lookups by endpoint and result.", "counter")
+	for _, k := range sortedKeys2(m.cache) {
+		fmt.Fprintf(&b, "%s%s %d\n", n, labels("endpoint", k[0], "result", k[1]), m.cache[k])
+	}
+
+	c, err := io.WriteString(w, b.String())
+	return int64(c), err
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labels(kv ...string) string {
+	parts := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		parts = append(parts, kv[i]+`="`+labelEscaper.Replace(kv[i+1])+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys2(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+	return keys
+}
+
+func sortedKeys3(m map[[3]string]uint64) [][3]string {
+	keys := make([][3]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		if a[1] != b[1] {
+			return a[1] < b[1]
+		}
+		return a[2] < b[2]
+	})
+	return keys
+}
+
+func sortedHistogramKeys(m map[[2]string]*histogram) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+	return keys
+}
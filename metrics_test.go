@@ -0,0 +1,126 @@
+package eos_contract_api_client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/health", "/health"},
+		{"/atomicassets/v1/assets", "/atomicassets/v1/assets"},
+		{"/atomicassets/v1/assets/1099667509880", "/atomicassets/v1/assets/{asset_id}"},
+		{"/atomicassets/v1/assets/1099667509880/logs", "/atomicassets/v1/assets/{asset_id}/logs"},
+		{"/atomicmarket/v1/assets/1099667509880/sales", "/atomicmarket/v1/assets/{asset_id}/sales"},
+		{"/unknown/path", "/unknown/path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, endpoint(tt.path))
+		})
+	}
+}
+
+func TestMetrics_WriteTo(t *testing.T) {
+	m := NewMetricsWithBuckets([]float64{0.1, 1})
+	m.Namespace = "test"
+
+	m.ObserveRequest(RequestMetrics{Method: "GET", Endpoint: "/health", StatusCode: 200, Duration: 50 * time.Millisecond, Size: 100, WireSize: 40, Encoding: "gzip"})
+	m.ObserveRequest(RequestMetrics{Method: "GET", Endpoint: "/health", StatusCode: 503, Duration: 500 * time.Millisecond, Size: 20, WireSize: 20, Retries: 2, ErrorKind: "server_unavailable"})
+	m.ObserveCache("/health", CacheHit)
+	m.ObserveCache("/health", CacheMiss)
+	m.ObserveCache("/health", CacheHit)
+
+	b := strings.Builder{}
+	_, err := m.WriteTo(&b)
+	require.NoError(t, err)
+
+	expected := `# HELP test_requests_total Number of requests by endpoint and status code.
+# TYPE test_requests_total counter
+test_requests_total{method="GET",endpoint="/health",code="200"} 1
+test_requests_total{method="GET",endpoint="/health",code="503"} 1
+# HELP test_errors_total Number of failed requests by endpoint and error kind.
+# TYPE test_errors_total counter
+test_errors_total{endpoint="/health",kind="server_unavailable"} 1
+# HELP test_request_duration_seconds Request latency by endpoint.
+# TYPE test_request_duration_seconds histogram
+test_request_duration_seconds_bucket{method="GET",endpoint="/health",le="0.1"} 1
+test_request_duration_seconds_bucket{method="GET",endpoint="/health",le="1"} 2
+test_request_duration_seconds_bucket{method="GET",endpoint="/health",le="+Inf"} 2
+test_request_duration_seconds_sum{method="GET",endpoint="/health"} 0.55
+test_request_duration_seconds_count{method="GET",endpoint="/health"} 2
+# HELP test_response_bytes_total Number of response body bytes decoded by endpoint.
+# TYPE test_response_bytes_total counter
+test_response_bytes_total{method="GET",endpoint="/health"} 120
+# HELP test_response_wire_bytes_total Number of response body bytes received before decompression by endpoint.
+# TYPE test_response_wire_bytes_total counter
+test_response_wire_bytes_total{method="GET",endpoint="/health"} 60
+# HELP test_response_encoding_total Number of responses received by endpoint and content encoding.
+# TYPE test_response_encoding_total counter
+test_response_encoding_total{endpoint="/health",encoding="gzip"} 1
+test_response_encoding_total{endpoint="/health",encoding="identity"} 1
+# HELP test_retries_total Number of retried requests by endpoint.
+# TYPE test_retries_total counter
+test_retries_total{method="GET",endpoint="/health"} 2
+# HELP test_cache_total Number of cache lookups by endpoint and result.
+# TYPE test_cache_total counter
+test_cache_total{endpoint="/health",result="hit"} 2
+test_cache_total{endpoint="/health",result="miss"} 1
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestLabels_Escape(t *testing.T) {
+	assert.Equal(t, `{a="x\"y\\z\n"}`, labels("a", "x\"y\\z\n"))
+}
+
+func TestClient_Metrics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		if req.URL.Path == "/atomicassets/v1/assets/2" {
+			res.WriteHeader(404)
+			res.Write([]byte(`{"success":false,"message":"Asset not found"}`))
+			return
+		}
+		res.Write([]byte(`{"success":true,"data":{}}`))
+	}))
+	defer srv.Close()
+
+	metrics := NewMetrics()
+
+	client := New(srv.URL)
+	client.Metrics = metrics
+	client.Cache = NewMemoryCache(10)
+	client.CacheTTL = CacheTTL{"/atomicassets/v1/assets": time.Minute}
+
+	client.GetAsset("1")
+	client.GetAsset("1")
+	client.GetAsset("2")
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	out := string(body)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, out, `eos_contract_api_client_requests_total{method="GET",endpoint="/atomicassets/v1/assets/{asset_id}",code="200"} 2`+"\n")
+	assert.Contains(t, out, `eos_contract_api_client_requests_total{method="GET",endpoint="/atomicassets/v1/assets/{asset_id}",code="404"} 1`+"\n")
+	assert.Contains(t, out, `eos_contract_api_client_errors_total{endpoint="/atomicassets/v1/assets/{asset_id}",kind="not_found"} 1`+"\n")
+	assert.Contains(t, out, `eos_contract_api_client_request_duration_seconds_count{method="GET",endpoint="/atomicassets/v1/assets/{asset_id}"} 3`+"\n")
+	assert.Contains(t, out, `eos_contract_api_client_response_bytes_total{method="GET",endpoint="/atomicassets/v1/assets/{asset_id}"} 52`+"\n")
+	assert.Contains(t, out, `eos_contract_api_client_cache_total{endpoint="/atomicassets/v1/assets/{asset_id}",result="hit"} 1`+"\n")
+	assert.Contains(t, out, `eos_contract_api_client_cache_total{endpoint="/atomicassets/v1/assets/{asset_id}",result="miss"} 2`+"\n")
+}
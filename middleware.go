@@ -0,0 +1,155 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Request is an API request before it is sent.
+type Request struct {
+	Context context.Context
+	Method  string
+
+	// BaseURL is the Url of the client sending the request.
+	// Middleware may change it to send the request elsewhere.
+	BaseURL string
+	Path    string
+
+	// Endpoint is the route of Path with parameters replaced by
+	// placeholders, for example "/atomicassets/v1/assets/{asset_id}".
+	Endpoint string
+
+	Query  url.Values
+	Header http.Header
+
+	// Timeout limits the total time of the request including retries.
+	Timeout time.Duration
+
+	// NoCache skips the cache lookup, the response is still stored.
+	NoCache bool
+
+	// MinBlock and MaxLag reject the request if the node is behind,
+	// see Freshness.
+	MinBlock int64
+	MaxLag   time.Duration
+
+	// Stream is set for Stream* requests. Their response is passed
+	// through middleware with an empty Body, it is decoded as it is read.
+	Stream bool
+
+	retries int
+
+	// proxy and unixSocket are how the client connects, see Curl.
+	proxy      string
+	unixSocket string
+}
+
+// URL returns the full url of the request.
+func (r *Request) URL() string {
+	uri := r.BaseURL + r.Path
+	if q := r.Query.Encode(); len(q) > 0 {
+		uri += "?" + q
+	}
+	return uri
+}
+
+// RequestOption changes a single request.
+type RequestOption func(*Request)
+
+// WithContext sets the context of the request.
+func WithContext(ctx context.Context) RequestOption {
+	return func(r *Request) {
+		r.Context = ctx
+	}
+}
+
+// WithRequestTimeout sets the timeout of the request.
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(r *Request) {
+		r.Timeout = d
+	}
+}
+
+// WithRequestHeader sets a header on the request.
+func WithRequestHeader(key string, value string) RequestOption {
+	return func(r *Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+// WithNoCache makes the request bypass the cache.
+func WithNoCache() RequestOption {
+	return func(r *Request) {
+		r.NoCache = true
+	}
+}
+
+// Response is a fully read API response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Latency is the time the request took.
+	Latency time.Duration
+
+	// WireSize is the size of the body as received before it was
+	// decompressed and Encoding its Content-Encoding.
+	// WireSize is 0 if the response was not received over the network.
+	WireSize int
+	Encoding string
+
+	// body is the unread body of a streamed response.
+	body io.ReadCloser
+}
+
+func (r *Response) Unmarshal(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// clone returns a copy of r that does not share its header or body.
+func (r *Response) clone() *Response {
+	cp := *r
+	cp.Header = r.Header.Clone()
+	if r.Body != nil {
+		cp.Body = append([]byte(nil), r.Body...)
+	}
+	return &cp
+}
+
+// release calls done once the response is no longer used. That is when
+// an unread body is closed, or at once if there is none.
+func (r *Response) release(done func()) {
+	if r == nil || r.body == nil {
+		done()
+		return
+	}
+	r.body = &releaseCloser{r.body, done}
+}
+
+type releaseCloser struct {
+	io.ReadCloser
+	done func()
+}
+
+func (rc *releaseCloser) Close() error {
+	err := rc.ReadCloser.Close()
+	rc.done()
+	return err
+}
+
+// Handler sends a request and returns the response.
+type Handler func(*Request) (*Response, error)
+
+// Middleware wraps a Handler. It may change the request before calling
+// next, inspect or replace the response, or return without calling next.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the client.
+func (c *Client) Use(mw ...Middleware) {
+	c.Middleware = append(c.Middleware, mw...)
+}
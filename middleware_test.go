@@ -0,0 +1,161 @@
+package eos_contract_api_client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_MiddlewareRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, "/atomicassets/v1/assets?limit=10&owner=farmersworld", req.URL.String())
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":[]}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			r.Header.Set("Authorization", "Bearer secret")
+			r.Query.Set("limit", "10")
+			return next(r)
+		}
+	})
+
+	_, err := client.GetAssets(AssetsRequestParams{Owner: "farmersworld"})
+	require.NoError(t, err)
+}
+
+func TestClient_MiddlewareOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	order := []string{}
+	mw := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(r *Request) (*Response, error) {
+				order = append(order, name+" before")
+				resp, err := next(r)
+				order = append(order, name+" after")
+				return resp, err
+			}
+		}
+	}
+
+	client := New(srv.URL)
+	client.Use(mw("first"), mw("second"))
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, order)
+}
+
+func TestClient_MiddlewareShortCircuit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			if r.Path == "/health" {
+				return &Response{StatusCode: 200, Body: []byte(`{"success":true,"data":{"version":"1.2.3"}}`)}, nil
+			}
+			return nil, errors.New("blocked")
+		}
+	})
+
+	h, err := client.GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", h.Data.Version)
+
+	_, err = client.GetAsset("1099667509880")
+	assert.EqualError(t, err, "blocked")
+}
+
+func TestClient_MiddlewareReplaceResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":{"version":"1.0.0"}}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			resp, err := next(r)
+			if err != nil {
+				return nil, err
+			}
+			assert.Equal(t, `{"success":true,"data":{"version":"1.0.0"}}`, string(resp.Body))
+			resp.Body = []byte(`{"success":true,"data":{"version":"2.0.0"}}`)
+			return resp, nil
+		}
+	})
+
+	h, err := client.GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", h.Data.Version)
+}
+
+func TestClient_MiddlewareBaseURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	breaker := NewCircuitBreaker(1, time.Minute)
+	client := New("http://127.0.0.1:1", WithCircuitBreaker(breaker))
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			r.BaseURL = srv.URL
+			return next(r)
+		}
+	})
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+
+	_, err = client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil })
+	require.NoError(t, err)
+
+	assert.Equal(t, CircuitClosed, breaker.State(srv.URL))
+	assert.Equal(t, CircuitClosed, breaker.State(client.Url))
+}
+
+func TestClient_MiddlewareHeaderValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, []string{"a", "b"}, req.Header.Values("X-Multi"))
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			r.Header.Add("X-Multi", "a")
+			r.Header.Add("X-Multi", "b")
+			return next(r)
+		}
+	})
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+
+	_, err = client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil })
+	require.NoError(t, err)
+}
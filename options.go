@@ -0,0 +1,134 @@
+package eos_contract_api_client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client created with New.
+type Option func(*Client)
+
+// WithTimeout sets the timeout of each http call.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = d
+	}
+}
+
+// WithHost sets the Host header sent with every request.
+func WithHost(host string) Option {
+	return func(c *Client) {
+		c.Host = host
+	}
+}
+
+// WithDefaultHeader sets a header that is sent with every request.
+func WithDefaultHeader(key string, value string) Option {
+	return func(c *Client) {
+		if c.Header == nil {
+			c.Header = http.Header{}
+		}
+		c.Header.Set(key, value)
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return WithDefaultHeader("User-Agent", ua)
+}
+
+// WithAuthToken sends token as a bearer token in the Authorization header.
+func WithAuthToken(token string) Option {
+	return WithDefaultHeader("Authorization", "Bearer "+token)
+}
+
+// WithRetry sets the retry policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.Retry = policy
+	}
+}
+
+// WithCache enables caching of responses, see Client.Cache.
+func WithCache(cache Cache, ttl CacheTTL) Option {
+	return func(c *Client) {
+		c.Cache = cache
+		c.CacheTTL = ttl
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for the http calls.
+func WithTransport(t http.RoundTripper) Option {
+	return func(c *Client) {
+		c.Transport = t
+	}
+}
+
+// WithProxy sends the http calls through an http, https or socks5 proxy.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		c.Proxy = proxy
+	}
+}
+
+// WithTLSConfig sets the tls configuration used for https connections.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.TLSConfig = cfg.Clone()
+	}
+}
+
+func tlsConfig(c *Client) *tls.Config {
+	if c.TLSConfig == nil {
+		c.TLSConfig = &tls.Config{}
+	}
+	return c.TLSConfig
+}
+
+// WithRootCAs verifies the server certificate against pool
+// instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) Option {
+	return func(c *Client) {
+		tlsConfig(c).RootCAs = pool
+	}
+}
+
+// WithClientCertificate presents cert to servers that ask for a client
+// certificate, see tls.LoadX509KeyPair.
+func WithClientCertificate(cert tls.Certificate) Option {
+	return func(c *Client) {
+		cfg := tlsConfig(c)
+		cfg.Certificates = append(cfg.Certificates, cert)
+	}
+}
+
+// WithUnixSocket connects to the API over the unix domain socket at path.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		c.UnixSocket = path
+	}
+}
+
+// WithKeepResponse keeps the raw body, headers and latency of every response.
+func WithKeepResponse() Option {
+	return func(c *Client) {
+		c.KeepResponse = true
+	}
+}
+
+// WithFreshness sets the freshness requirements of every request.
+func WithFreshness(f Freshness) Option {
+	return func(c *Client) {
+		c.Freshness = f
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker of the client.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(c *Client) {
+		c.Breaker = b
+	}
+}
@@ -0,0 +1,182 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNew_Options(t *testing.T) {
+	cache := NewMemoryCache(1)
+	ttl := CacheTTL{"/": time.Second}
+	retry := RetryPolicy{MaxRetries: 3}
+	transport := roundTripFunc(http.DefaultTransport.RoundTrip)
+
+	client := New("http://localhost",
+		WithTimeout(time.Second),
+		WithHost("my-custom-host"),
+		WithUserAgent("my-agent/1.0"),
+		WithDefaultHeader("X-Custom", "value"),
+		WithAuthToken("secret"),
+		WithRetry(retry),
+		WithCache(cache, ttl),
+		WithTransport(transport),
+	)
+
+	assert.Equal(t, "http://localhost", client.Url)
+	assert.Equal(t, time.Second, client.Timeout)
+	assert.Equal(t, "my-custom-host", client.Host)
+	assert.Equal(t, http.Header{
+		"User-Agent":    []string{"my-agent/1.0"},
+		"X-Custom":      []string{"value"},
+		"Authorization": []string{"Bearer secret"},
+	}, client.Header)
+	assert.Equal(t, 3, client.Retry.MaxRetries)
+	assert.Equal(t, cache, client.Cache)
+	assert.Equal(t, ttl, client.CacheTTL)
+	assert.NotNil(t, client.Transport)
+}
+
+func TestClient_Headers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "my-agent/1.0", req.Header.Get("User-Agent"))
+		assert.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+		assert.Equal(t, "request", req.Header.Get("X-Custom"))
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithUserAgent("my-agent/1.0"), WithAuthToken("secret"), WithDefaultHeader("X-Custom", "client"))
+
+	_, err := client.GetHealth(WithRequestHeader("X-Custom", "request"))
+	require.NoError(t, err)
+
+	// Request headers must not leak into the client defaults.
+	assert.Equal(t, "client", client.Header.Get("X-Custom"))
+}
+
+func TestClient_Transport(t *testing.T) {
+	client := New("http://api.example", WithTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "http://api.example/health", r.URL.String())
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       http.NoBody,
+			Request:    r,
+		}, nil
+	})))
+
+	_, err := client.GetHealth()
+	assert.EqualError(t, err, "unexpected end of JSON input")
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithTimeout(10*time.Millisecond))
+	_, err := client.GetHealth()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+
+	client = New(srv.URL)
+	_, err = client.GetHealth(WithRequestTimeout(10 * time.Millisecond))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func TestClient_NoCache(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithCache(NewMemoryCache(10), CacheTTL{"/health": time.Minute}))
+
+	client.GetHealth()
+	client.GetHealth(WithNoCache())
+	client.GetHealth()
+
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestClient_Retry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		res.Header().Add("Content-type", "application/json")
+		switch n {
+		case 1:
+			res.WriteHeader(503)
+			res.Write([]byte(`{}`))
+		case 2:
+			res.Header().Add("Retry-After", "0")
+			res.WriteHeader(429)
+			res.Write([]byte(`{}`))
+		default:
+			res.Write([]byte(`{"success":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	tracer := &testTracer{}
+	client := New(srv.URL, WithRetry(RetryPolicy{MaxRetries: 3, MinBackoff: time.Millisecond}))
+	client.Tracer = tracer
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(3), calls)
+	require.Len(t, tracer.spans, 1)
+	assert.Equal(t, 2, tracer.spans[0].Retries)
+}
+
+func TestClient_RetryNotRetryable(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		res.Header().Add("Content-type", "application/json")
+		res.WriteHeader(404)
+		res.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithRetry(RetryPolicy{MaxRetries: 3}))
+
+	_, err := client.GetHealth()
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, p.backoff(0, nil))
+	assert.Equal(t, 200*time.Millisecond, p.backoff(1, nil))
+	assert.Equal(t, 800*time.Millisecond, p.backoff(3, nil))
+	assert.Equal(t, time.Second, p.backoff(4, nil))
+	assert.Equal(t, time.Second, p.backoff(100, nil))
+
+	err := &APIError{StatusCode: 429, Header: http.Header{"Retry-After": []string{"0"}}}
+	assert.Equal(t, time.Duration(0), p.backoff(2, err))
+}
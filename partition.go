@@ -0,0 +1,240 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AssetPartition is a range of assets crawled by CrawlAssetsParallel.
+// Non zero fields replace the corresponding fields of the request params.
+type AssetPartition struct {
+	// LowerBound and UpperBound are inclusive asset ids.
+	LowerBound string
+	UpperBound string
+
+	// After and Before are exclusive unix timestamps in milliseconds.
+	After  int
+	Before int
+}
+
+// SplitAssetIDs splits the asset ids from lower to upper (inclusive)
+// into n partitions of roughly equal size.
+func SplitAssetIDs(lower uint64, upper uint64, n int) []AssetPartition {
+	if n < 1 || upper < lower {
+		return nil
+	}
+
+	step := (upper-lower)/uint64(n) + 1
+	parts := []AssetPartition{}
+	for start := lower; ; start += step {
+		end := start + step - 1
+		if end > upper || end < start {
+			end = upper
+		}
+
+		parts = append(parts, AssetPartition{
+			LowerBound: strconv.FormatUint(start, 10),
+			UpperBound: strconv.FormatUint(end, 10),
+		})
+
+		if end == upper {
+			return parts
+		}
+	}
+}
+
+// SplitTime splits the time between after and before (exclusive)
+// into n partitions of roughly equal length.
+func SplitTime(after time.Time, before time.Time, n int) []AssetPartition {
+	a, b := int(after.UnixNano()/1e6), int(before.UnixNano()/1e6)
+	if n < 1 || b-a < 2 {
+		return nil
+	}
+
+	if n > b-a-1 {
+		n = b - a - 1
+	}
+
+	parts := make([]AssetPartition, n)
+	for i := range parts {
+		parts[i].After = a + (b-a)*i/n
+		parts[i].Before = a + (b-a)*(i+1)/n + 1
+	}
+	parts[n-1].Before = b
+	return parts
+}
+
+// CrawlOptions configures CrawlAssetsParallel.
+type CrawlOptions struct {
+	// Workers is the number of partitions crawled at the same time,
+	// defaults to 4.
+	Workers int
+
+	// Ordered emits the assets of each partition only after all assets
+	// of the partitions before it. Assets within a partition are always in
+	// asset_id order, so ordered partitions from SplitAssetIDs are emitted
+	// in asset_id order. Partitions that finish early are kept in memory.
+	Ordered bool
+
+	// MaxRetries is the number of times a failed partition is resumed
+	// from its last asset before the crawl fails.
+	MaxRetries int
+}
+
+type partitionPage struct {
+	index  int
+	assets []Asset
+	done   bool
+	err    error
+}
+
+func (p AssetPartition) params(params AssetsRequestParams) AssetsRequestParams {
+	if len(p.LowerBound) > 0 {
+		params.LowerBound = p.LowerBound
+	}
+	if len(p.UpperBound) > 0 {
+		params.UpperBound = p.UpperBound
+	}
+	if p.After != 0 {
+		params.After = p.After
+	}
+	if p.Before != 0 {
+		params.Before = p.Before
+	}
+	return params
+}
+
+func (c *Client) crawlPartition(ctx context.Context, index int, params AssetsRequestParams, retries int, out chan<- partitionPage) {
+	send := func(p partitionPage) bool {
+		select {
+		case out <- p:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	checkpoint := ""
+	for attempt := 0; ; attempt++ {
+		cr := c.CrawlAssets(params, checkpoint, WithContext(ctx))
+
+		page := []Asset{}
+		for cr.Next() {
+			page = append(page, cr.Asset())
+			if len(page) >= cr.params.Limit {
+				if !send(partitionPage{index: index, assets: page}) {
+					return
+				}
+				page = []Asset{}
+			}
+		}
+
+		if len(page) > 0 && !send(partitionPage{index: index, assets: page}) {
+			return
+		}
+
+		checkpoint = cr.Checkpoint()
+		if cr.Err() == nil || attempt >= retries || ctx.Err() != nil {
+			send(partitionPage{index: index, done: true, err: cr.Err()})
+			return
+		}
+	}
+}
+
+// CrawlAssetsParallel crawls the assets matching params in each partition
+// concurrently and calls fn for every asset. fn is never called
+// concurrently. The crawl stops at the first error returned by fn
+// or a partition that fails after CrawlOptions.MaxRetries retries.
+func (c *Client) CrawlAssetsParallel(ctx context.Context, params AssetsRequestParams, partitions []AssetPartition, options CrawlOptions, fn func(Asset) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := options.Workers
+	if workers < 1 {
+		workers = 4
+	}
+
+	out := make(chan partitionPage)
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers && i < len(partitions); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				c.crawlPartition(ctx, index, partitions[index].params(params), options.MaxRetries, out)
+			}
+		}()
+	}
+
+	// Partitions are started in order so the next partition to emit
+	// always has a worker.
+	go func() {
+		defer close(jobs)
+		for i := range partitions {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	pending := make([][]Asset, len(partitions))
+	done := make([]bool, len(partitions))
+	next := 0
+
+	emit := func(assets []Asset) error {
+		for _, a := range assets {
+			if err := fn(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	for p := range out {
+		if err != nil {
+			continue
+		}
+
+		if p.err != nil {
+			err = p.err
+		} else if !options.Ordered {
+			err = emit(p.assets)
+		} else if p.index == next {
+			// The current partition is emitted as it arrives.
+			err = emit(p.assets)
+		} else {
+			pending[p.index] = append(pending[p.index], p.assets...)
+		}
+
+		done[p.index] = done[p.index] || p.done
+
+		for options.Ordered && err == nil && next < len(partitions) && done[next] {
+			next++
+			if next < len(partitions) {
+				err = emit(pending[next])
+				pending[next] = nil
+			}
+		}
+
+		if err != nil {
+			cancel()
+		}
+	}
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
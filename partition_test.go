@@ -0,0 +1,209 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partitionServer serves assets 1..n minted at id seconds, filtered by
+// lower_bound, upper_bound, after and before. fail is called for every
+// request and a non zero status code is returned as an error.
+func partitionServer(t *testing.T, n uint64, fail func(q map[string]string) int) *httptest.Server {
+	mu := sync.Mutex{}
+
+	return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		q := map[string]string{}
+		for k := range req.URL.Query() {
+			q[k] = req.URL.Query().Get(k)
+		}
+
+		mu.Lock()
+		code := 0
+		if fail != nil {
+			code = fail(q)
+		}
+		mu.Unlock()
+
+		res.Header().Add("Content-type", "application/json")
+		if code > 0 {
+			res.WriteHeader(code)
+			res.Write([]byte(`{"success":false,"message":"failed"}`))
+			return
+		}
+
+		limit, _ := strconv.Atoi(q["limit"])
+		lower, _ := strconv.ParseUint(q["lower_bound"], 10, 64)
+		upper, err := strconv.ParseUint(q["upper_bound"], 10, 64)
+		if err != nil {
+			upper = n
+		}
+		after, _ := strconv.ParseUint(q["after"], 10, 64)
+		before, err := strconv.ParseUint(q["before"], 10, 64)
+		if err != nil {
+			before = ^uint64(0)
+		}
+
+		data := []map[string]string{}
+		for id := lower; id <= upper && id <= n && len(data) < limit; id++ {
+			if minted := id * 1000; id > 0 && minted > after && minted < before {
+				data = append(data, map[string]string{
+					"asset_id":       strconv.FormatUint(id, 10),
+					"minted_at_time": strconv.FormatUint(minted, 10),
+				})
+			}
+		}
+
+		b, err := json.Marshal(map[string]interface{}{"success": true, "data": data})
+		require.NoError(t, err)
+		res.Write(b)
+	}))
+}
+
+func assetIDs(from int, to int) []string {
+	ids := []string{}
+	for i := from; i <= to; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	return ids
+}
+
+func TestSplitAssetIDs(t *testing.T) {
+	assert.Equal(t, []AssetPartition{
+		{LowerBound: "1", UpperBound: "4"},
+		{LowerBound: "5", UpperBound: "8"},
+		{LowerBound: "9", UpperBound: "10"},
+	}, SplitAssetIDs(1, 10, 3))
+
+	assert.Equal(t, []AssetPartition{
+		{LowerBound: "7", UpperBound: "7"},
+	}, SplitAssetIDs(7, 7, 3))
+
+	max := ^uint64(0)
+	parts := SplitAssetIDs(0, max, 2)
+	require.Len(t, parts, 2)
+	assert.Equal(t, strconv.FormatUint(max, 10), parts[1].UpperBound)
+
+	assert.Nil(t, SplitAssetIDs(10, 1, 3))
+	assert.Nil(t, SplitAssetIDs(1, 10, 0))
+}
+
+func TestSplitTime(t *testing.T) {
+	after := time.Unix(0, 0)
+
+	assert.Equal(t, []AssetPartition{
+		{After: 0, Before: 4},
+		{After: 3, Before: 7},
+		{After: 6, Before: 10},
+	}, SplitTime(after, after.Add(10*time.Millisecond), 3))
+
+	// Never more partitions than there are timestamps.
+	assert.Len(t, SplitTime(after, after.Add(3*time.Millisecond), 5), 2)
+	assert.Nil(t, SplitTime(after, after.Add(time.Millisecond), 1))
+}
+
+func TestClient_CrawlAssetsParallel(t *testing.T) {
+	srv := partitionServer(t, 50, nil)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	t.Run("Ordered", func(t *testing.T) {
+		got := []string{}
+		err := client.CrawlAssetsParallel(context.Background(), AssetsRequestParams{Limit: 3}, SplitAssetIDs(1, 50, 7), CrawlOptions{Workers: 3, Ordered: true}, func(a Asset) error {
+			got = append(got, a.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, assetIDs(1, 50), got)
+	})
+
+	t.Run("Unordered", func(t *testing.T) {
+		got := []string{}
+		err := client.CrawlAssetsParallel(context.Background(), AssetsRequestParams{Limit: 3}, SplitAssetIDs(1, 50, 7), CrawlOptions{Workers: 3}, func(a Asset) error {
+			got = append(got, a.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.ElementsMatch(t, assetIDs(1, 50), got)
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		got := []string{}
+		parts := SplitTime(time.Unix(10, 0), time.Unix(31, 0), 4)
+		err := client.CrawlAssetsParallel(context.Background(), AssetsRequestParams{Limit: 2}, parts, CrawlOptions{Ordered: true}, func(a Asset) error {
+			got = append(got, a.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, assetIDs(11, 30), got)
+	})
+}
+
+func TestClient_CrawlAssetsParallelRetry(t *testing.T) {
+	requests := map[string]int{}
+	srv := partitionServer(t, 20, func(q map[string]string) int {
+		key := q["lower_bound"] + ":" + q["upper_bound"]
+		requests[key]++
+
+		// Fail the second page of the partition starting at 11 once.
+		if key == "13:20" && requests[key] == 1 {
+			return http.StatusBadGateway
+		}
+		return 0
+	})
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	got := []string{}
+	err := client.CrawlAssetsParallel(context.Background(), AssetsRequestParams{Limit: 2}, SplitAssetIDs(1, 20, 2), CrawlOptions{Ordered: true, MaxRetries: 1}, func(a Asset) error {
+		got = append(got, a.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, assetIDs(1, 20), got)
+
+	// Only the failed page is requested again.
+	assert.Equal(t, 1, requests["11:20"])
+	assert.Equal(t, 2, requests["13:20"])
+}
+
+func TestClient_CrawlAssetsParallelError(t *testing.T) {
+	srv := partitionServer(t, 20, func(q map[string]string) int {
+		if q["lower_bound"] == "13" {
+			return http.StatusBadGateway
+		}
+		return 0
+	})
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	err := client.CrawlAssetsParallel(context.Background(), AssetsRequestParams{Limit: 2}, SplitAssetIDs(1, 20, 2), CrawlOptions{MaxRetries: 2}, func(a Asset) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrServerUnavailable)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = client.CrawlAssetsParallel(context.Background(), AssetsRequestParams{Limit: 2}, SplitAssetIDs(1, 10, 2), CrawlOptions{}, func(a Asset) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+}
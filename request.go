@@ -29,6 +29,7 @@ type AssetsRequestParams struct {
 	MatchImmutableName      string   `qs:"match_immutable_name,omitempty"`
 	MatchMutableName        string   `qs:"match_mutable_name,omitempty"`
 	HideTemplatesByAccounts string   `qs:"hide_templates_by_accounts,omitempty"`
+	IDs                     string   `qs:"ids,omitempty"`
 
 	IsTransferable          bool `qs:"is_transferable,omitempty"`
 	IsBurnable              bool `qs:"is_burnable,omitempty"`
@@ -43,6 +44,7 @@ type AssetsRequestParams struct {
 	Before int `qs:"before,omitempty"`
 	After  int `qs:"after,omitempty"`
 
+	Page  int    `qs:"page,omitempty"`
 	Limit int    `qs:"limit,omitempty"`
 	Order string `qs:"order,omitempty"`
 	Sort  string `qs:"sort,omitempty"`
@@ -52,5 +54,7 @@ type AssetSalesRequestParams struct {
 	Buyer  string    `qs:"buyer,omitempty"`
 	Seller string    `qs:"seller,omitempty"`
 	Symbol string    `qs:"symbol,omitempty"`
+	Page   int       `qs:"page,omitempty"`
+	Limit  int       `qs:"limit,omitempty"`
 	Order  SortOrder `qs:"order,omitempty"`
 }
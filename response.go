@@ -1,6 +1,11 @@
 package eos_contract_api_client
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
 	null "gopkg.in/guregu/null.v4"
 )
 
@@ -8,6 +13,12 @@ import (
 
 type HTTPResponse struct {
 	HTTPStatusCode int
+
+	// Set only when Client.KeepResponse is enabled.
+	// Raw is not kept for streamed responses.
+	Header  http.Header     `json:"-"`
+	Raw     json.RawMessage `json:"-"`
+	Latency time.Duration   `json:"-"`
 }
 
 func (resp *HTTPResponse) IsError() bool {
@@ -19,6 +30,34 @@ func (resp *HTTPResponse) IsError() bool {
 type APIError struct {
 	Success null.Bool   `json:"success"`
 	Message null.String `json:"message"`
+
+	// Request and response information, not part of the payload.
+	// Body is truncated to at most MaxErrorBodySize bytes.
+	StatusCode int         `json:"-"`
+	Method     string      `json:"-"`
+	URL        string      `json:"-"`
+	Header     http.Header `json:"-"`
+	Body       []byte      `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message.Valid && len(e.Message.String) > 0 {
+		return "API Error: " + e.Message.String
+	}
+	return fmt.Sprintf("API Error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is makes errors.Is match the sentinel error for the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerUnavailable:
+		return isServerUnavailable(e.StatusCode)
+	}
+	return false
 }
 
 type APIResponse struct {
@@ -0,0 +1,86 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// The zero value never retries.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+
+	// MinBackoff is the delay before the first retry, it is doubled
+	// for each retry up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Retryable reports if err should be retried, DefaultRetryable is used if nil.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryable retries rate limited requests, unavailable servers
+// and errors where no response was received.
+func DefaultRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrUnmatchedRequest) {
+		return false
+	}
+
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerUnavailable) {
+		return true
+	}
+
+	var apiErr *APIError
+	var ctErr *ContentTypeError
+	return !errors.As(err, &apiErr) && !errors.As(err, &ctErr)
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return DefaultRetryable(err)
+}
+
+func (p RetryPolicy) backoff(retry int, err error) time.Duration {
+	d := p.MinBackoff << uint(retry)
+	if d < p.MinBackoff {
+		// Overflow.
+		d = p.MaxBackoff
+	}
+
+	// Respect Retry-After from the server if it is given in seconds.
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Header != nil {
+		if s, e := strconv.Atoi(apiErr.Header.Get("Retry-After")); e == nil && s >= 0 {
+			d = time.Duration(s) * time.Second
+		}
+	}
+
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+func (c *Client) fetchRetry(r *Request, query string, header http.Header) (*Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := c.fetchBreaker(r, query, header)
+		if err == nil || retry >= c.Retry.MaxRetries || !c.Retry.retryable(err) {
+			return resp, err
+		}
+
+		t := time.NewTimer(c.Retry.backoff(retry, err))
+		select {
+		case <-r.Context.Done():
+			t.Stop()
+			return nil, err
+		case <-t.C:
+		}
+		r.retries++
+	}
+}
@@ -0,0 +1,216 @@
+package eos_contract_api_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// stream sends a request and calls next for each element in the "data"
+// array of the response, decoding directly from the response body.
+// The Stream* callbacks can return an error to stop the stream.
+//
+// Streamed requests go through the same middleware, breaker, retries
+// and freshness checks as other requests but are not cached or coalesced.
+// With KeepResponse the header and the latency until the header was
+// received are kept, Raw is always nil.
+func (c *Client) stream(method string, path string, params interface{}, res *APIResponse, next func(*json.Decoder) error, opts []RequestOption) error {
+	resp, err := c.exec(method, path, params, opts, true)
+	if err != nil {
+		return err
+	}
+
+	res.HTTPStatusCode = resp.StatusCode
+
+	// The body is never buffered, so Raw is not kept.
+	if c.KeepResponse {
+		res.Header = resp.Header
+		res.Latency = resp.Latency
+	}
+
+	// Middleware may have replaced the response with a buffered one.
+	body := resp.body
+	if body == nil {
+		body = ioutil.NopCloser(bytes.NewReader(resp.Body))
+	}
+	defer body.Close()
+
+	return decodeStream(json.NewDecoder(body), res, next)
+}
+
+// fetchStream sends a request and returns a response with the body unread.
+// Unsuccessful responses are read and returned as errors like fetch does.
+func (c *Client) fetchStream(r *Request, query string, header http.Header) (resp *Response, err error) {
+	ctx := r.Context
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer func() { resp.release(cancel) }()
+	}
+
+	uri := r.BaseURL + r.Path
+	if len(query) > 0 {
+		uri += "?" + query
+	}
+
+	hr, err := http.NewRequestWithContext(ctx, r.Method, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	hr.Header = header
+	hr.Host = header.Get("Host")
+
+	hresp, err := c.httpClient().GetClient().Do(hr)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding := hresp.Header.Get("Content-Encoding")
+	body, err := decompress(encoding, hresp.Body)
+	if err != nil {
+		hresp.Body.Close()
+		return nil, err
+	}
+
+	if hresp.StatusCode < 200 || hresp.StatusCode > 299 || !isContentType(hresp.Header.Get("Content-Type"), "application/json") {
+		b, _ := ioutil.ReadAll(io.LimitReader(body, MaxErrorBodySize))
+		body.Close()
+		hresp.Body.Close()
+		return nil, checkResponse(r.Method, uri, hresp.StatusCode, hresp.Header, b)
+	}
+
+	respHeader := hresp.Header
+	if len(encoding) > 0 {
+		respHeader = respHeader.Clone()
+		respHeader.Del("Content-Encoding")
+		respHeader.Del("Content-Length")
+	}
+
+	return &Response{
+		StatusCode: hresp.StatusCode,
+		Header:     respHeader,
+		Encoding:   encoding,
+		body:       &streamBody{body, hresp.Body},
+	}, nil
+}
+
+// streamBody closes both the decompressed body and the connection body.
+type streamBody struct {
+	io.ReadCloser
+	raw io.Closer
+}
+
+func (b *streamBody) Close() error {
+	b.ReadCloser.Close()
+	return b.raw.Close()
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected '%s' in json stream, got %v", delim, t)
+	}
+	return nil
+}
+
+// decodeStream decodes an api response object, "success" and "query_time"
+// are stored in res and next is called for each element of "data".
+func decodeStream(dec *json.Decoder, res *APIResponse, next func(*json.Decoder) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t {
+		case "data":
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				if err := next(dec); err != nil {
+					return err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return err
+			}
+		case "success":
+			err = dec.Decode(&res.Success)
+		case "query_time":
+			err = dec.Decode(&res.QueryTime)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+//	StreamAssets - Streams "/atomicassets/v1/assets" from API
+//
+// ---------------------------------------------------------
+func (c *Client) StreamAssets(params AssetsRequestParams, fn func(Asset) error, opts ...RequestOption) (APIResponse, error) {
+	var res APIResponse
+
+	err := c.stream("GET", "/atomicassets/v1/assets", params, &res, func(dec *json.Decoder) error {
+		var asset Asset
+		if err := dec.Decode(&asset); err != nil {
+			return err
+		}
+		return fn(asset)
+	}, opts)
+
+	return res, err
+}
+
+//	StreamAssetLog - Streams "/atomicassets/v1/assets/{asset_id}/logs" from API
+//
+// ---------------------------------------------------------
+func (c *Client) StreamAssetLog(asset_id string, params LogRequestParams, fn func(Log) error, opts ...RequestOption) (APIResponse, error) {
+	var res APIResponse
+
+	err := c.stream("GET", "/atomicassets/v1/assets/"+asset_id+"/logs", params, &res, func(dec *json.Decoder) error {
+		var log Log
+		if err := dec.Decode(&log); err != nil {
+			return err
+		}
+		return fn(log)
+	}, opts)
+
+	return res, err
+}
+
+//	StreamAssetSales - Streams "/atomicmarket/v1/assets/{asset_id}/sales" from API
+//
+// ---------------------------------------------------------
+func (c *Client) StreamAssetSales(asset_id string, params AssetSalesRequestParams, fn func(AssetSale) error, opts ...RequestOption) (APIResponse, error) {
+	var res APIResponse
+
+	err := c.stream("GET", "/atomicmarket/v1/assets/"+asset_id+"/sales", params, &res, func(dec *json.Decoder) error {
+		var sale AssetSale
+		if err := dec.Decode(&sale); err != nil {
+			return err
+		}
+		return fn(sale)
+	}, opts)
+
+	return res, err
+}
@@ -0,0 +1,293 @@
+package eos_contract_api_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func assetsPayload(n int) []byte {
+	assets := make([]Asset, n)
+	for i := range assets {
+		assets[i] = asset1
+		assets[i].ID = fmt.Sprint(1099667509880 + i)
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"success":    true,
+		"data":       assets,
+		"query_time": 1669043479123,
+	})
+	return b
+}
+
+func assetsServer(payload []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		res.Write(payload)
+	}))
+}
+
+func TestClient_StreamAssets(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/atomicassets/v1/assets?limit=3&owner=farmersworld", req.URL.String())
+		res.Header().Add("Content-type", "application/json")
+		res.Write(assetsPayload(3))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	ids := []string{}
+	res, err := client.StreamAssets(AssetsRequestParams{Owner: "farmersworld", Limit: 3}, func(a Asset) error {
+		assert.Equal(t, asset1.Template, a.Template)
+		ids = append(ids, a.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.HTTPStatusCode)
+	assert.True(t, res.Success)
+	assert.Equal(t, time.Date(2022, time.November, 21, 15, 11, 19, 123, time.UTC), res.QueryTime.Time())
+	assert.Equal(t, []string{"1099667509880", "1099667509881", "1099667509882"}, ids)
+}
+
+func TestClient_StreamKeepResponse(t *testing.T) {
+	srv := assetsServer(assetsPayload(2))
+	defer srv.Close()
+
+	client := New(srv.URL, WithKeepResponse())
+
+	res, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Greater(t, res.Latency, time.Duration(0))
+	assert.Nil(t, res.Raw)
+
+	// Nothing is kept by default.
+	client.KeepResponse = false
+	res, err = client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil })
+	require.NoError(t, err)
+	assert.Nil(t, res.Header)
+	assert.Zero(t, res.Latency)
+}
+
+func TestClient_StreamStop(t *testing.T) {
+	srv := assetsServer(assetsPayload(10))
+	defer srv.Close()
+
+	client := New(srv.URL)
+	stop := errors.New("stop")
+
+	n := 0
+	_, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error {
+		n++
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestClient_StreamAssetLogAndSales(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Header().Add("Content-type", "application/json")
+		switch req.URL.Path {
+		case "/atomicassets/v1/assets/1/logs":
+			res.Write([]byte(`{"success":true,"data":[{"log_id":"1","name":"logmint"},{"log_id":"2","name":"logtransfer"}],"query_time":1}`))
+		case "/atomicmarket/v1/assets/1/sales":
+			res.Write([]byte(`{"data":[{"sale_id":"10","price":"100"}],"success":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	logs := []string{}
+	_, err := client.StreamAssetLog("1", LogRequestParams{}, func(l Log) error {
+		logs = append(logs, l.Name)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logmint", "logtransfer"}, logs)
+
+	sales := []string{}
+	res, err := client.StreamAssetSales("1", AssetSalesRequestParams{}, func(s AssetSale) error {
+		sales = append(sales, s.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, res.Success)
+	assert.Equal(t, []string{"10"}, sales)
+}
+
+func TestClient_StreamMiddleware(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "test", req.Header.Get("X-Middleware"))
+
+		// Fail the first call to make sure streams are retried.
+		if atomic.AddInt32(&calls, 1) == 1 {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		res.Header().Add("Content-type", "application/json")
+		res.Write(assetsPayload(2))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithRetry(RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond}))
+
+	var streamed bool
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			streamed = r.Stream
+			r.Header.Set("X-Middleware", "test")
+			return next(r)
+		}
+	})
+
+	n := 0
+	_, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error {
+		n++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, streamed)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_StreamReplacedResponse(t *testing.T) {
+	client := New("http://localhost")
+	client.Use(func(next Handler) Handler {
+		return func(r *Request) (*Response, error) {
+			return &Response{StatusCode: 200, Body: assetsPayload(3)}, nil
+		}
+	})
+
+	n := 0
+	_, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error {
+		n++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestClient_StreamFreshness(t *testing.T) {
+	head := int64(100)
+	srv, _, requests := healthServer(&head, 0, 0)
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	_, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil }, WithMinBlock(101))
+	assert.ErrorIs(t, err, ErrStale)
+
+	_, err = client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil }, WithMinBlock(100))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestClient_StreamErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        int
+		contentType string
+		body        string
+		err         string
+	}{
+		{"api error", 500, "application/json", `{"success":false,"message":"Some internal error"}`, "API Error: Some internal error"},
+		{"html", 502, "text/html", `<html></html>`, "API Error: 502 Bad Gateway"},
+		{"content type", 200, "text/html", `<html></html>`, "invalid content-type 'text/html', expected 'application/json'"},
+		{"not an object", 200, "application/json", `[]`, "expected '{' in json stream, got ["},
+		{"data not an array", 200, "application/json", `{"data":{}}`, "expected '[' in json stream, got {"},
+		{"truncated", 200, "application/json", `{"data":[{"asset_id":"1"},{"asse`, "unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Header().Add("Content-type", tt.contentType)
+				res.WriteHeader(tt.code)
+				res.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client := New(srv.URL)
+
+			_, err := client.StreamAssets(AssetsRequestParams{}, func(a Asset) error { return nil })
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestDecodeStream_SkipsUnknown(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"extra":{"a":[1,2]},"success":true,"data":[1,2,3],"more":null}`))
+
+	res := APIResponse{}
+	sum := 0
+	err := decodeStream(dec, &res, func(dec *json.Decoder) error {
+		var n int
+		err := dec.Decode(&n)
+		sum += n
+		return err
+	})
+
+	require.NoError(t, err)
+	assert.True(t, res.Success)
+	assert.Equal(t, 6, sum)
+}
+
+func BenchmarkGetAssets(b *testing.B) {
+	srv := assetsServer(assetsPayload(1000))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		res, err := client.GetAssets(AssetsRequestParams{Limit: 1000})
+		if err != nil || len(res.Data) != 1000 {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamAssets(b *testing.B) {
+	srv := assetsServer(assetsPayload(1000))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n := 0
+		_, err := client.StreamAssets(AssetsRequestParams{Limit: 1000}, func(a Asset) error {
+			n++
+			return nil
+		})
+		if err != nil || n != 1000 {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Tracer starts spans for client requests.
+type Tracer interface {
+	// StartSpan starts a span for r. The returned context is used for the
+	// rest of the request, a tracer that creates child spans should store
+	// the new trace parent with ContextWithTraceParent or set the
+	// traceparent header on r itself.
+	StartSpan(ctx context.Context, r *Request) (context.Context, Span)
+}
+
+type Span interface {
+	End(info SpanInfo)
+}
+
+// SpanInfo describes a finished request.
+type SpanInfo struct {
+	Method     string
+	Path       string
+	StatusCode int
+
+	// Retries is the number of times the request was retried.
+	Retries int
+
+	// Size is the size of the response body in bytes.
+	Size int
+
+	Err error
+}
+
+type traceContextKey struct{}
+
+type traceContext struct {
+	parent string
+	state  string
+}
+
+// ContextWithTraceParent returns a copy of ctx that carries the W3C
+// traceparent and tracestate header values. They are sent with every
+// request made with the context. Invalid traceparent values are ignored.
+func ContextWithTraceParent(ctx context.Context, traceparent string, tracestate string) context.Context {
+	if !ValidTraceParent(traceparent) {
+		return ctx
+	}
+	return context.WithValue(ctx, traceContextKey{}, traceContext{parent: traceparent, state: tracestate})
+}
+
+// TraceParentFromContext returns the traceparent and tracestate stored in ctx.
+func TraceParentFromContext(ctx context.Context) (string, string) {
+	if tc, ok := ctx.Value(traceContextKey{}).(traceContext); ok {
+		return tc.parent, tc.state
+	}
+	return "", ""
+}
+
+// ValidTraceParent reports whether s is a valid W3C traceparent header value.
+func ValidTraceParent(s string) bool {
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 {
+		return false
+	}
+
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+
+	// Version 00 has exactly four fields, "ff" is forbidden.
+	if len(version) != 2 || version == "ff" || (version == "00" && len(parts) != 4) {
+		return false
+	}
+
+	if len(traceID) != 32 || len(parentID) != 16 || len(flags) != 2 {
+		return false
+	}
+
+	for _, p := range []string{version, traceID, parentID, flags} {
+		if strings.ToLower(p) != p {
+			return false
+		}
+		if _, err := hex.DecodeString(p); err != nil {
+			return false
+		}
+	}
+
+	// All zero ids are invalid.
+	return strings.Trim(traceID, "0") != "" && strings.Trim(parentID, "0") != ""
+}
+
+func injectTraceParent(ctx context.Context, h http.Header) {
+	if parent, state := TraceParentFromContext(ctx); len(parent) > 0 {
+		h.Set("traceparent", parent)
+		if len(state) > 0 {
+			h.Set("tracestate", state)
+		} else {
+			h.Del("tracestate")
+		}
+	}
+}
+
+func (c *Client) traced(next Handler) Handler {
+	return func(r *Request) (*Response, error) {
+		parent, _ := TraceParentFromContext(r.Context)
+
+		ctx, span := c.Tracer.StartSpan(r.Context, r)
+		r.Context = ctx
+
+		if p, _ := TraceParentFromContext(ctx); p != parent {
+			injectTraceParent(ctx, r.Header)
+		}
+
+		resp, err := next(r)
+
+		info := SpanInfo{
+			Method:     r.Method,
+			Path:       r.Path,
+			StatusCode: statusCode(resp, err),
+			Retries:    r.retries,
+			Err:        err,
+		}
+
+		if resp != nil {
+			info.Size = len(resp.Body)
+		}
+
+		span.End(info)
+		return resp, err
+	}
+}
+
+// OTelSpan is the part of an OpenTelemetry style span used by OTelTracer.
+type OTelSpan interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// OTelTracer adapts an OpenTelemetry style tracer to Tracer.
+//
+// Attributes are named after the OpenTelemetry http semantic conventions.
+type OTelTracer struct {
+	// Start starts a span named name as a child of the span in ctx.
+	Start func(ctx context.Context, name string) (context.Context, OTelSpan)
+
+	// Inject writes the propagation headers for the span in ctx to h,
+	// for example with a propagation.TraceContext propagator.
+	// If nil, only a traceparent stored with ContextWithTraceParent is sent.
+	Inject func(ctx context.Context, h http.Header)
+}
+
+type otelSpan struct {
+	span OTelSpan
+}
+
+func (t OTelTracer) StartSpan(ctx context.Context, r *Request) (context.Context, Span) {
+	ctx, span := t.Start(ctx, r.Method+" "+r.Endpoint)
+
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("url.path", r.Path)
+	if len(r.Query) > 0 {
+		span.SetAttribute("url.query", r.Query.Encode())
+	}
+
+	if t.Inject != nil {
+		t.Inject(ctx, r.Header)
+	}
+
+	return ctx, otelSpan{span: span}
+}
+
+func (s otelSpan) End(info SpanInfo) {
+	if info.StatusCode > 0 {
+		s.span.SetAttribute("http.status_code", info.StatusCode)
+	}
+	s.span.SetAttribute("http.resend_count", info.Retries)
+	s.span.SetAttribute("http.response_content_length", info.Size)
+
+	if info.Err != nil {
+		s.span.RecordError(info.Err)
+	}
+	s.span.End()
+}
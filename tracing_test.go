@@ -0,0 +1,158 @@
+package eos_contract_api_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+type testTracer struct {
+	child string
+	spans []SpanInfo
+}
+
+type testSpan struct {
+	tracer *testTracer
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, r *Request) (context.Context, Span) {
+	if len(t.child) > 0 {
+		ctx = ContextWithTraceParent(ctx, t.child, "")
+	}
+	return ctx, testSpan{tracer: t}
+}
+
+func (s testSpan) End(info SpanInfo) {
+	s.tracer.spans = append(s.tracer.spans, info)
+}
+
+type testOTelSpan struct {
+	name  string
+	attrs map[string]interface{}
+	errs  []error
+	ended bool
+}
+
+func (s *testOTelSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testOTelSpan) RecordError(err error)                      { s.errs = append(s.errs, err) }
+func (s *testOTelSpan) End()                                       { s.ended = true }
+
+func TestValidTraceParent(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{testTraceParent, true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01", false},
+		{"00-xyz92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidTraceParent(tt.value))
+		})
+	}
+}
+
+func TestClient_PropagateTraceParent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, testTraceParent, req.Header.Get("traceparent"))
+		assert.Equal(t, "vendor=value", req.Header.Get("tracestate"))
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL)
+
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent, "vendor=value")
+
+	_, err := client.GetHealth(WithContext(ctx))
+	require.NoError(t, err)
+}
+
+func TestClient_Tracer(t *testing.T) {
+	child := "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, child, req.Header.Get("traceparent"))
+		res.Header().Add("Content-type", "application/json")
+		if req.URL.Path == "/health" {
+			res.Write([]byte(`{"success":true}`))
+			return
+		}
+		res.WriteHeader(404)
+		res.Write([]byte(`{"success":false,"message":"Asset not found"}`))
+	}))
+	defer srv.Close()
+
+	tracer := &testTracer{child: child}
+	client := New(srv.URL)
+	client.Tracer = tracer
+
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent, "")
+
+	_, err := client.GetHealth(WithContext(ctx))
+	require.NoError(t, err)
+
+	_, err = client.GetAsset("1", WithContext(ctx))
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, SpanInfo{Method: "GET", Path: "/health", StatusCode: 200, Size: 16}, tracer.spans[0])
+	assert.Equal(t, "/atomicassets/v1/assets/1", tracer.spans[1].Path)
+	assert.Equal(t, 404, tracer.spans[1].StatusCode)
+	assert.True(t, errors.Is(tracer.spans[1].Err, ErrNotFound))
+}
+
+func TestClient_OTelTracer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "injected", req.Header.Get("traceparent"))
+		res.Header().Add("Content-type", "application/json")
+		res.Write([]byte(`{"success":true,"data":[]}`))
+	}))
+	defer srv.Close()
+
+	var span *testOTelSpan
+
+	client := New(srv.URL)
+	client.Tracer = OTelTracer{
+		Start: func(ctx context.Context, name string) (context.Context, OTelSpan) {
+			span = &testOTelSpan{name: name, attrs: map[string]interface{}{}}
+			return ctx, span
+		},
+		Inject: func(ctx context.Context, h http.Header) {
+			h.Set("traceparent", "injected")
+		},
+	}
+
+	_, err := client.GetAssets(AssetsRequestParams{Owner: "farmersworld"})
+	require.NoError(t, err)
+
+	require.NotNil(t, span)
+	assert.True(t, span.ended)
+	assert.Empty(t, span.errs)
+	assert.Equal(t, "GET /atomicassets/v1/assets", span.name)
+	assert.Equal(t, map[string]interface{}{
+		"http.method":                  "GET",
+		"url.path":                     "/atomicassets/v1/assets",
+		"url.query":                    "owner=farmersworld",
+		"http.status_code":             200,
+		"http.resend_count":            0,
+		"http.response_content_length": 26,
+	}, span.attrs)
+}
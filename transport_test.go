@@ -0,0 +1,152 @@
+package eos_contract_api_client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func healthHandler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Add("Content-type", "application/json")
+	res.Write([]byte(`{"success":true,"data":{"version":"1.0.0"}}`))
+}
+
+// clientCertificate creates a self signed client certificate.
+func clientCertificate(t *testing.T) (tls.Certificate, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, cert
+}
+
+func TestClient_RootCAs(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(healthHandler))
+	defer srv.Close()
+
+	_, err := New(srv.URL).GetHealth()
+	var certErr x509.UnknownAuthorityError
+	assert.ErrorAs(t, err, &certErr)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	h, err := New(srv.URL, WithRootCAs(pool)).GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", h.Data.Version)
+}
+
+func TestClient_ClientCertificate(t *testing.T) {
+	cert, parsed := clientCertificate(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(parsed)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(healthHandler))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	_, err := New(srv.URL, WithRootCAs(pool)).GetHealth()
+	assert.Error(t, err)
+
+	h, err := New(srv.URL, WithRootCAs(pool), WithClientCertificate(cert)).GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", h.Data.Version)
+}
+
+func TestClient_TLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(healthHandler))
+	defer srv.Close()
+
+	cfg := &tls.Config{InsecureSkipVerify: true}
+	client := New(srv.URL, WithTLSConfig(cfg), WithRootCAs(x509.NewCertPool()))
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+
+	// The config passed to WithTLSConfig is not changed.
+	assert.Nil(t, cfg.RootCAs)
+}
+
+func TestClient_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.sock")
+
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "eosio-contract-api", req.Host)
+		healthHandler(res, req)
+	}))
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.Start()
+	defer srv.Close()
+
+	h, err := New("http://eosio-contract-api", WithUnixSocket(path)).GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", h.Data.Version)
+}
+
+func TestClient_Proxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		// Requests through a proxy use the absolute url.
+		assert.Equal(t, "http://api.example/health", req.RequestURI)
+		healthHandler(res, req)
+	}))
+	defer proxy.Close()
+
+	u, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	h, err := New("http://api.example", WithProxy(u)).GetHealth()
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", h.Data.Version)
+}
+
+func TestClient_TransportOverridesOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(healthHandler))
+	defer srv.Close()
+
+	client := New(srv.URL,
+		WithUnixSocket(filepath.Join(t.TempDir(), "missing.sock")),
+		WithTransport(roundTripFunc(http.DefaultTransport.RoundTrip)),
+	)
+
+	_, err := client.GetHealth()
+	require.NoError(t, err)
+}